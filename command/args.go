@@ -2,11 +2,14 @@ package command
 
 import (
 	"fmt"
+	"go/token"
 	"os"
 	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/alecthomas/kingpin"
 	"github.com/efritz/go-genlib/paths"
@@ -21,6 +24,25 @@ type Options struct {
 	OutputImportPath string
 	Prefix           string
 	Force            bool
+	Check            bool
+	DryRun           bool
+	AllOrNothing     bool
+	Prune            bool
+	Lenient          bool
+	Tests            string
+	Timeout          time.Duration
+	CacheDir         string
+	Jobs             int
+	ImportAliases    map[string]string
+	ReservedNames    []string
+	BuildTags        []string
+	GOOS             string
+	GOARCH           string
+	BuildFlags       []string
+	BuildConstraint  bool
+	AnnotatedOnly    bool
+	ConfigFile       string
+	GeneratorOptions map[string]string
 }
 
 var GoIdentifierPattern = regexp.MustCompile("^[A-Za-z]([A-Za-z0-9_]*[A-Za-z])?$")
@@ -31,15 +53,16 @@ func parseArgs(
 	version string,
 	argHook ArgHookFunc,
 	argValidator ArgValidatorFunc,
-) (*Options, error) {
+) ([]*Options, error) {
 	app := kingpin.New(name, description).Version(version)
 
 	opts := &Options{
-		ImportPaths: []string{},
-		Interfaces:  []string{},
+		ImportPaths:   []string{},
+		Interfaces:    []string{},
+		ImportAliases: map[string]string{},
 	}
 
-	app.Arg("path", "The import paths used to search for eligible interfaces").Required().StringsVar(&opts.ImportPaths)
+	app.Arg("path", "The import paths or package patterns (e.g. ./...) used to search for eligible interfaces").StringsVar(&opts.ImportPaths)
 	app.Flag("package", "The name of the generated package. It will be inferred from the output options by default.").Short('p').StringVar(&opts.PkgName)
 	app.Flag("interfaces", "A whitelist of interfaces to generate given the import paths.").Short('i').StringsVar(&opts.Interfaces)
 	app.Flag("dirname", "The target output directory. Each mock will be written to a unique file.").Short('d').StringVar(&opts.OutputDir)
@@ -47,12 +70,91 @@ func parseArgs(
 	app.Flag("import-path", "The import path of the generated package. It will be inferred from the tarrget directory by default.").StringVar(&opts.PkgName)
 	app.Flag("prefix", "A prefix used in the name of each mock struct. Should be TitleCase by convention.").StringVar(&opts.Prefix)
 	app.Flag("force", "Do not abort if a write to disk would overwrite an existing file.").Short('f').BoolVar(&opts.Force)
+	app.Flag("check", "Do not write to disk. Fail if any generated file is missing or out of date.").BoolVar(&opts.Check)
+	app.Flag("dry-run", "Do not write to disk. Print the files that would be created or overwritten.").BoolVar(&opts.DryRun)
+	app.Flag("all-or-nothing", "Restore every file written by a job if writing any of its files fails.").BoolVar(&opts.AllOrNothing)
+	app.Flag("prune", "Delete files in the target output directory that were previously generated by this tool but not by this run.").BoolVar(&opts.Prune)
+	app.Flag("lenient", "Do not abort on package errors. Types whose declarations contain errors are skipped.").BoolVar(&opts.Lenient)
+	app.Flag("timeout", "Abort if loading and generation take longer than this duration.").DurationVar(&opts.Timeout)
+	app.Flag("import-alias", "The alias of an imported package in generated code, as path=alias.").StringMapVar(&opts.ImportAliases)
+	app.Flag("reserve", "An identifier that must not be used as the name of an imported package.").StringsVar(&opts.ReservedNames)
+	app.Flag("jobs", "The number of files rendered and written concurrently. The interface generator must be safe for concurrent use.").Default("1").IntVar(&opts.Jobs)
+	app.Flag("cache-dir", "Reuse types extracted from unchanged packages by a previous run stored in this directory.").StringVar(&opts.CacheDir)
+	app.Flag("tests", "Include _test.go files: none, internal (in-package tests), external (_test packages), or all.").Default("none").EnumVar(&opts.Tests, "none", "internal", "external", "all")
+	app.Flag("tags", "Build tags used to select the files of each package.").StringsVar(&opts.BuildTags)
+	app.Flag("goos", "The target operating system used to select the files of each package.").StringVar(&opts.GOOS)
+	app.Flag("goarch", "The target architecture used to select the files of each package.").StringVar(&opts.GOARCH)
+	app.Flag("build-flag", "An additional flag passed to the build tool when loading packages.").StringsVar(&opts.BuildFlags)
+	app.Flag("build-constraint", "Add a //go:build constraint matching --tags, --goos, and --goarch to generated files.").BoolVar(&opts.BuildConstraint)
+	app.Flag("annotated", "Only generate types annotated with a //genlib:generate directive.").BoolVar(&opts.AnnotatedOnly)
+	app.Flag("config", "A YAML or JSON file describing multiple generation jobs. Flags supply defaults for each job.").StringVar(&opts.ConfigFile)
 	argHook(app)
 
 	if _, err := app.Parse(os.Args[1:]); err != nil {
 		return nil, err
 	}
 
+	if opts.ConfigFile == "" {
+		if len(opts.ImportPaths) == 0 {
+			kingpin.Fatalf("required argument 'path' not provided, try --help")
+		}
+
+		if err := validate(opts, argValidator); err != nil {
+			return nil, err
+		}
+
+		return []*Options{opts}, nil
+	}
+
+	if len(opts.ImportPaths) > 0 {
+		kingpin.Fatalf("path and config are mutually exclusive, try --help")
+	}
+
+	config, err := readConfig(opts.ConfigFile)
+	if err != nil {
+		return nil, err
+	}
+
+	allOpts := []*Options{}
+	for i, job := range config.Jobs {
+		jobOpts := job.apply(opts)
+		if err := validate(jobOpts, argValidator); err != nil {
+			return nil, fmt.Errorf("job %d: %s", i+1, err.Error())
+		}
+
+		allOpts = append(allOpts, jobOpts)
+	}
+
+	if err := validateSharedOutputDirs(allOpts); err != nil {
+		return nil, err
+	}
+
+	return allOpts, nil
+}
+
+// validateSharedOutputDirs rejects pruning a directory that another job writes to.
+func validateSharedOutputDirs(allOpts []*Options) error {
+	for i, opts := range allOpts {
+		if !opts.Prune {
+			continue
+		}
+
+		for j, other := range allOpts {
+			if i != j && opts.OutputDir == other.OutputDir {
+				return fmt.Errorf(
+					"jobs %d and %d both write to %s, which cannot be pruned",
+					min(i, j)+1,
+					max(i, j)+1,
+					paths.GetRelativePath(opts.OutputDir),
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validate(opts *Options, argValidator ArgValidatorFunc) error {
 	validators := []ArgValidatorFunc{
 		validateOutputPaths,
 		validateOptions,
@@ -61,18 +163,36 @@ func parseArgs(
 
 	for _, f := range validators {
 		if fatal, err := f(opts); err != nil {
-			if !fatal {
+			if !fatal && opts.ConfigFile == "" {
 				kingpin.Fatalf("%s, try --help", err.Error())
 			}
 
-			return nil, err
+			return err
 		}
 	}
 
-	return opts, nil
+	return nil
 }
 
 func validateOptions(opts *Options) (bool, error) {
+	if opts.Check && opts.DryRun {
+		return false, fmt.Errorf("check and dry-run are mutually exclusive")
+	}
+
+	if opts.Prune && opts.OutputFilename != "" {
+		return false, fmt.Errorf("prune requires an output directory")
+	}
+
+	if opts.Jobs < 1 {
+		return false, fmt.Errorf("jobs must be at least 1")
+	}
+
+	opts.BuildTags = splitBuildTags(opts.BuildTags)
+
+	if opts.BuildConstraint && len(opts.BuildTags) == 0 && opts.GOOS == "" && opts.GOARCH == "" {
+		return false, fmt.Errorf("build-constraint requires tags, goos, or goarch")
+	}
+
 	if opts.PkgName != "" && opts.OutputImportPath != "" {
 		return false, fmt.Errorf("package name and output import path are mutually exclusive")
 	}
@@ -98,9 +218,57 @@ func validateOptions(opts *Options) (bool, error) {
 		return false, fmt.Errorf("prefix `%s` is illegal", opts.Prefix)
 	}
 
+	if err := validateImportAliases(opts.ImportAliases, opts.ReservedNames); err != nil {
+		return false, err
+	}
+
 	return false, nil
 }
 
+func validateImportAliases(aliases map[string]string, reserved []string) error {
+	importPaths := []string{}
+	for importPath := range aliases {
+		importPaths = append(importPaths, importPath)
+	}
+	sort.Strings(importPaths)
+
+	taken := map[string]string{}
+	for _, importPath := range importPaths {
+		alias := aliases[importPath]
+		if !token.IsIdentifier(alias) || alias == "_" {
+			return fmt.Errorf("import alias `%s` for %s is illegal", alias, importPath)
+		}
+
+		for _, name := range reserved {
+			if alias == name {
+				return fmt.Errorf("import alias `%s` for %s is reserved", alias, importPath)
+			}
+		}
+
+		if other, ok := taken[alias]; ok {
+			return fmt.Errorf("import alias `%s` is used for both %s and %s", alias, other, importPath)
+		}
+
+		taken[alias] = importPath
+	}
+
+	return nil
+}
+
+// splitBuildTags accepts both repeated tag flags and comma-separated lists.
+func splitBuildTags(values []string) []string {
+	tags := []string{}
+	for _, value := range values {
+		for _, tag := range strings.Split(value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	return tags
+}
+
 func validateOutputPaths(opts *Options) (bool, error) {
 	wd, err := os.Getwd()
 	if err != nil {
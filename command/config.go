@@ -0,0 +1,110 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	// Config describes a set of generation jobs run by a single invocation.
+	// Config files may be written in YAML or JSON.
+	Config struct {
+		Jobs []*JobConfig `yaml:"jobs"`
+	}
+
+	// JobConfig describes a single generation job. Unset fields default to the
+	// value of the corresponding command line flag.
+	JobConfig struct {
+		ImportPaths      []string          `yaml:"paths"`
+		Interfaces       []string          `yaml:"interfaces"`
+		PkgName          string            `yaml:"package"`
+		OutputDir        string            `yaml:"dirname"`
+		OutputFilename   string            `yaml:"filename"`
+		OutputImportPath string            `yaml:"import-path"`
+		Prefix           string            `yaml:"prefix"`
+		AnnotatedOnly    bool              `yaml:"annotated"`
+		ImportAliases    map[string]string `yaml:"import-aliases"`
+		GeneratorOptions map[string]string `yaml:"options"`
+	}
+)
+
+func readConfig(filename string) (*Config, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s (%s)", filename, err.Error())
+	}
+
+	// Unknown keys are rejected so that a misspelled setting is not silently ignored.
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+
+	config := &Config{}
+	if err := decoder.Decode(config); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to parse config file %s (%s)", filename, err.Error())
+	}
+
+	if len(config.Jobs) == 0 {
+		return nil, fmt.Errorf("config file %s defines no jobs", filename)
+	}
+
+	for i, job := range config.Jobs {
+		if len(job.ImportPaths) == 0 {
+			return nil, fmt.Errorf("job %d in config file %s has no paths", i+1, filename)
+		}
+	}
+
+	return config, nil
+}
+
+func (c *JobConfig) apply(opts *Options) *Options {
+	jobOpts := *opts
+	jobOpts.ImportPaths = c.ImportPaths
+
+	if len(c.Interfaces) > 0 {
+		jobOpts.Interfaces = c.Interfaces
+	}
+
+	if c.PkgName != "" {
+		jobOpts.PkgName = c.PkgName
+	}
+
+	if c.OutputDir != "" || c.OutputFilename != "" {
+		jobOpts.OutputDir = c.OutputDir
+		jobOpts.OutputFilename = c.OutputFilename
+	}
+
+	if c.OutputImportPath != "" {
+		jobOpts.OutputImportPath = c.OutputImportPath
+	}
+
+	if c.Prefix != "" {
+		jobOpts.Prefix = c.Prefix
+	}
+
+	if c.AnnotatedOnly {
+		jobOpts.AnnotatedOnly = true
+	}
+
+	if len(c.ImportAliases) > 0 {
+		importAliases := map[string]string{}
+		for path, alias := range opts.ImportAliases {
+			importAliases[path] = alias
+		}
+
+		for path, alias := range c.ImportAliases {
+			importAliases[path] = alias
+		}
+
+		jobOpts.ImportAliases = importAliases
+	}
+
+	if c.GeneratorOptions != nil {
+		jobOpts.GeneratorOptions = c.GeneratorOptions
+	}
+
+	return &jobOpts
+}
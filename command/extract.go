@@ -1,7 +1,9 @@
 package command
 
 import (
+	"context"
 	"fmt"
+	"log"
 	"strings"
 	"unicode"
 
@@ -10,20 +12,32 @@ import (
 )
 
 func Extract(
+	ctx context.Context,
 	typeGetter types.TypeGetter,
 	importPaths []string,
 	targetNames []string,
+	annotatedOnly bool,
+	configs ...extraction.ConfigFunc,
 ) ([]*types.Interface, error) {
-	extractor, err := extraction.NewExtractor()
+	extractor, err := extraction.NewExtractor(configs...)
 	if err != nil {
 		return nil, err
 	}
 
-	pkgs, err := extractor.Extract(importPaths)
+	pkgs, err := extractor.Extract(ctx, importPaths)
 	if err != nil {
 		return nil, err
 	}
 
+	return extract(pkgs, typeGetter, targetNames, annotatedOnly)
+}
+
+func extract(
+	pkgs *types.Packages,
+	typeGetter types.TypeGetter,
+	targetNames []string,
+	annotatedOnly bool,
+) ([]*types.Interface, error) {
 	ifaces := []*types.Interface{}
 
 	for _, name := range pkgs.GetNames() {
@@ -32,6 +46,10 @@ func Extract(
 		}
 
 		iface, err := typeGetter(pkgs, name)
+		if ambiguousErr, ok := err.(*types.AmbiguousTypeError); ok {
+			iface, err = resolveAmbiguousType(pkgs, typeGetter, ambiguousErr, targetNames, annotatedOnly)
+		}
+
 		if err != nil {
 			return nil, err
 		}
@@ -40,6 +58,18 @@ func Extract(
 			continue
 		}
 
+		if annotatedOnly && !iface.Directives.Has(types.GenerateDirective) {
+			continue
+		}
+
+		if prefix, ok := iface.Directives.Get(types.GenerateDirective, "prefix"); ok && !GoIdentifierPattern.Match([]byte(prefix)) {
+			return nil, fmt.Errorf(
+				"type '%s' has an illegal prefix '%s'",
+				name,
+				prefix,
+			)
+		}
+
 		for _, method := range iface.Methods {
 			if !unicode.IsUpper([]rune(method.Name)[0]) {
 				return nil, fmt.Errorf(
@@ -56,6 +86,47 @@ func Extract(
 	return ifaces, nil
 }
 
+// resolveAmbiguousType chooses among the declarations of a name in several packages.
+func resolveAmbiguousType(
+	pkgs *types.Packages,
+	typeGetter types.TypeGetter,
+	ambiguousErr *types.AmbiguousTypeError,
+	targetNames []string,
+	annotatedOnly bool,
+) (*types.Interface, error) {
+	annotated := []*types.Interface{}
+	annotatedIDs := []string{}
+	for _, id := range ambiguousErr.PackageIDs {
+		iface, err := typeGetter(pkgs.Subset(id), ambiguousErr.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if iface != nil && iface.Directives.Has(types.GenerateDirective) {
+			annotated = append(annotated, iface)
+			annotatedIDs = append(annotatedIDs, id)
+		}
+	}
+
+	if annotatedOnly {
+		switch len(annotated) {
+		case 0:
+			return nil, nil
+		case 1:
+			return annotated[0], nil
+		}
+
+		return nil, &types.AmbiguousTypeError{Name: ambiguousErr.Name, PackageIDs: annotatedIDs}
+	}
+
+	if len(targetNames) > 0 || len(annotated) > 0 {
+		return nil, ambiguousErr
+	}
+
+	log.Printf("warning: %s, skipping\n", ambiguousErr.Error())
+	return nil, nil
+}
+
 func shouldInclude(name string, targetNames []string) bool {
 	for _, v := range targetNames {
 		if strings.ToLower(v) == strings.ToLower(name) {
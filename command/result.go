@@ -0,0 +1,57 @@
+package command
+
+import "fmt"
+
+// Result lists the files created, updated, left unchanged, and deleted by a
+// generator, and the existing files a dry run without --force could not write.
+type Result struct {
+	Created   []string
+	Updated   []string
+	Unchanged []string
+	Deleted   []string
+	Conflicts []string
+}
+
+func (r *Result) Merge(other *Result) {
+	if other == nil {
+		return
+	}
+
+	r.Created = append(r.Created, other.Created...)
+	r.Updated = append(r.Updated, other.Updated...)
+	r.Unchanged = append(r.Unchanged, other.Unchanged...)
+	r.Deleted = append(r.Deleted, other.Deleted...)
+	r.Conflicts = append(r.Conflicts, other.Conflicts...)
+}
+
+// DryRunSummary describes the changes that would have been made.
+func (r *Result) DryRunSummary() string {
+	summary := fmt.Sprintf(
+		"dry run, no files written: %d would be created, %d updated, %d unchanged, %d deleted",
+		len(r.Created),
+		len(r.Updated),
+		len(r.Unchanged),
+		len(r.Deleted),
+	)
+
+	if len(r.Conflicts) > 0 {
+		summary += fmt.Sprintf(", %d conflicts, rerun with --force", len(r.Conflicts))
+	}
+
+	return summary
+}
+
+// CheckSummary describes the files found to be up to date.
+func (r *Result) CheckSummary() string {
+	return fmt.Sprintf("generated files are up to date: %d checked", len(r.Unchanged))
+}
+
+func (r *Result) Summary() string {
+	return fmt.Sprintf(
+		"generated files: %d created, %d updated, %d unchanged, %d deleted",
+		len(r.Created),
+		len(r.Updated),
+		len(r.Unchanged),
+		len(r.Deleted),
+	)
+}
@@ -1,11 +1,14 @@
 package command
 
 import (
+	"context"
 	"fmt"
+	"log"
 	"strings"
 
 	"github.com/alecthomas/kingpin"
 
+	"github.com/efritz/go-genlib/extraction"
 	"github.com/efritz/go-genlib/types"
 )
 
@@ -15,12 +18,13 @@ type (
 		argValidator ArgValidatorFunc
 	}
 
-	Generator        func(ifaces []*types.Interface, opts *Options) error
+	Generator        func(ctx context.Context, ifaces []*types.Interface, opts *Options) (*Result, error)
 	ArgHookFunc      func(app *kingpin.Application)
 	ArgValidatorFunc func(opts *Options) (bool, error)
 )
 
 func Run(
+	ctx context.Context,
 	name string,
 	description string,
 	version string,
@@ -37,7 +41,7 @@ func Run(
 		f(config)
 	}
 
-	opts, err := parseArgs(
+	allOpts, err := parseArgs(
 		name,
 		description,
 		version,
@@ -49,14 +53,93 @@ func Run(
 		return err
 	}
 
-	ifaces, err := Extract(
+	if allOpts[0].Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, allOpts[0].Timeout)
+		defer cancel()
+	}
+
+	testPackages, err := extraction.ParseTestPackages(allOpts[0].Tests)
+	if err != nil {
+		return err
+	}
+
+	extractor, err := extraction.NewExtractor(
+		extraction.WithLenient(allOpts[0].Lenient),
+		extraction.WithTestPackages(testPackages),
+		extraction.WithBuildTags(allOpts[0].BuildTags),
+		extraction.WithGOOS(allOpts[0].GOOS),
+		extraction.WithGOARCH(allOpts[0].GOARCH),
+		extraction.WithBuildFlags(allOpts[0].BuildFlags),
+		extraction.WithCacheDir(allOpts[0].CacheDir),
+	)
+	if err != nil {
+		return err
+	}
+
+	// Load the packages of every job at once so that each is loaded once.
+	batches := [][]string{}
+	for _, opts := range allOpts {
+		batches = append(batches, opts.ImportPaths)
+	}
+
+	allPkgs, err := extractor.ExtractBatch(ctx, batches)
+	if err != nil {
+		return err
+	}
+
+	// In check mode every job is run so that all stale files are reported at once.
+	errs := []string{}
+	result := &Result{}
+	for i, opts := range allOpts {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		jobResult, err := runJob(ctx, allPkgs[i], typeGetter, generator, opts)
+		if err != nil {
+			if !opts.Check || ctx.Err() != nil {
+				return err
+			}
+
+			errs = append(errs, err.Error())
+		}
+
+		result.Merge(jobResult)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+
+	switch {
+	case allOpts[0].Check:
+		log.Printf("%s\n", result.CheckSummary())
+	case allOpts[0].DryRun:
+		log.Printf("%s\n", result.DryRunSummary())
+	default:
+		log.Printf("%s\n", result.Summary())
+	}
+
+	return nil
+}
+
+func runJob(
+	ctx context.Context,
+	pkgs *types.Packages,
+	typeGetter types.TypeGetter,
+	generator Generator,
+	opts *Options,
+) (*Result, error) {
+	ifaces, err := extract(
+		pkgs,
 		typeGetter,
-		opts.ImportPaths,
 		opts.Interfaces,
+		opts.AnnotatedOnly,
 	)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	nameMap := map[string]struct{}{}
@@ -66,9 +149,9 @@ func Run(
 
 	for _, name := range opts.Interfaces {
 		if _, ok := nameMap[strings.ToLower(name)]; !ok {
-			return fmt.Errorf("type '%s' not found in supplied import paths", name)
+			return nil, fmt.Errorf("type '%s' not found in supplied import paths", name)
 		}
 	}
 
-	return generator(ifaces, opts)
+	return generator(ctx, ifaces, opts)
 }
@@ -0,0 +1,406 @@
+package extraction
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	gotypes "go/types"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/gcexportdata"
+	gopackages "golang.org/x/tools/go/packages"
+
+	"github.com/efritz/go-genlib/types"
+)
+
+// cacheVersion must change whenever the content of a cache entry changes.
+const cacheVersion = "2"
+
+type (
+	// cacheEntry holds the export data of a package and the annotations of its types.
+	cacheEntry struct {
+		ImportPath        string
+		ExportData        []byte
+		Types             []*cachedInterface
+		MethodSets        []*cachedInterface
+		PointerMethodSets []*cachedInterface
+	}
+
+	cachedInterface struct {
+		Name       string
+		Doc        string
+		Directives types.Directives
+		Filename   string
+		Line       int
+		Methods    []*cachedMethod
+	}
+
+	cachedMethod struct {
+		Name       string
+		Doc        string
+		Directives types.Directives
+		Filename   string
+		Line       int
+	}
+
+	cacheKeys struct {
+		base    []byte
+		keys    map[string]string
+		files   map[string]string
+		overlay map[string][]byte
+	}
+)
+
+// loadCached restores the packages from the cache or returns the keys to store.
+func (e *Extractor) loadCached(ctx context.Context, importPaths []string) (map[string][]*types.Package, map[string]string, error) {
+	mode := gopackages.NeedName | gopackages.NeedFiles | gopackages.NeedImports |
+		gopackages.NeedDeps | gopackages.NeedModule | gopackages.NeedForTest
+
+	packageConfig := e.packagesConfig(ctx, mode)
+	pkgs, err := gopackages.Load(packageConfig, importPaths...)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, nil, ctxErr
+	}
+
+	// Errors are reported by the full load.
+	if err != nil || len(collectPackageErrors(pkgs)) > 0 {
+		return nil, nil, nil
+	}
+
+	pkgs = e.testPackages.selectPackages(pkgs)
+
+	hasher := newCacheKeys(packageConfig, e.testPackages)
+	keys := map[string]string{}
+	for _, pkg := range pkgs {
+		keys[pkg.ID] = hasher.key(pkg)
+	}
+
+	extracted := map[*gopackages.Package]*types.Package{}
+	for _, pkg := range pkgs {
+		cached, ok := e.readCacheEntry(keys[pkg.ID], pkg.ID)
+		if !ok {
+			return nil, keys, nil
+		}
+
+		extracted[pkg] = cached
+	}
+
+	loaded, err := e.assignPackages(importPaths, pkgs, extracted)
+	return loaded, keys, err
+}
+
+func (e *Extractor) readCacheEntry(key, id string) (*types.Package, bool) {
+	content, err := os.ReadFile(filepath.Join(e.cacheDir, key+".json"))
+	if err != nil {
+		return nil, false
+	}
+
+	entry := &cacheEntry{}
+	if err := json.Unmarshal(content, entry); err != nil {
+		return nil, false
+	}
+
+	pkgType, err := gcexportdata.Read(
+		bytes.NewReader(entry.ExportData),
+		token.NewFileSet(),
+		map[string]*gotypes.Package{},
+		entry.ImportPath,
+	)
+
+	if err != nil {
+		return nil, false
+	}
+
+	restore := func(
+		cached []*cachedInterface,
+		deconstruct func(name string, typ gotypes.Type) *types.Interface,
+	) (map[string]*types.Interface, bool) {
+		ifaces := map[string]*types.Interface{}
+		for _, c := range cached {
+			obj, ok := pkgType.Scope().Lookup(c.Name).(*gotypes.TypeName)
+			if !ok {
+				return nil, false
+			}
+
+			iface := deconstruct(c.Name, obj.Type())
+			if iface == nil || !restoreAnnotations(iface, c) {
+				return nil, false
+			}
+
+			iface.TypeParams = []*types.TypeParam{}
+			if named, ok := obj.Type().(*gotypes.Named); ok {
+				iface.TypeParams = types.DeconstructTypeParams(named.TypeParams())
+			}
+
+			ifaces[c.Name] = iface
+		}
+
+		return ifaces, true
+	}
+
+	decls, ok1 := restore(entry.Types, func(name string, typ gotypes.Type) *types.Interface {
+		switch t := typ.Underlying().(type) {
+		case *gotypes.Struct:
+			return types.DeconstructStruct(name, entry.ImportPath, t)
+		case *gotypes.Interface:
+			return types.DeconstructInterface(name, entry.ImportPath, t)
+		}
+
+		return nil
+	})
+
+	methodSets, ok2 := restore(entry.MethodSets, func(name string, typ gotypes.Type) *types.Interface {
+		if named, ok := typ.(*gotypes.Named); ok {
+			return types.DeconstructMethodSet(name, entry.ImportPath, instantiateWithTypeParams(named))
+		}
+
+		return nil
+	})
+
+	pointerMethodSets, ok3 := restore(entry.PointerMethodSets, func(name string, typ gotypes.Type) *types.Interface {
+		if named, ok := typ.(*gotypes.Named); ok {
+			return types.DeconstructMethodSet(name, entry.ImportPath, gotypes.NewPointer(instantiateWithTypeParams(named)))
+		}
+
+		return nil
+	})
+
+	if !ok1 || !ok2 || !ok3 {
+		return nil, false
+	}
+
+	return types.NewPackage(id, entry.ImportPath, decls, methodSets, pointerMethodSets), true
+}
+
+func restoreAnnotations(iface *types.Interface, cached *cachedInterface) bool {
+	if len(iface.Methods) != len(cached.Methods) {
+		return false
+	}
+
+	iface.Doc = cached.Doc
+	iface.Directives = cached.Directives
+	iface.Filename = cached.Filename
+	iface.Line = cached.Line
+
+	for i, method := range iface.Methods {
+		if method.Name != cached.Methods[i].Name {
+			return false
+		}
+
+		method.Doc = cached.Methods[i].Doc
+		method.Directives = cached.Methods[i].Directives
+		method.Filename = cached.Methods[i].Filename
+		method.Line = cached.Methods[i].Line
+	}
+
+	return true
+}
+
+func (e *Extractor) writeCacheEntry(key string, pkg *gopackages.Package, extracted *types.Package) error {
+	entry := &cacheEntry{ImportPath: pkg.PkgPath}
+	for _, target := range []struct {
+		ifaces map[string]*types.Interface
+		cached *[]*cachedInterface
+	}{
+		{extracted.Types, &entry.Types},
+		{extracted.MethodSets, &entry.MethodSets},
+		{extracted.PointerMethodSets, &entry.PointerMethodSets},
+	} {
+		for _, name := range sortedNames(target.ifaces) {
+			iface := target.ifaces[name]
+
+			obj := pkg.Types.Scope().Lookup(name)
+			if obj == nil || !samePosition(e.fset.Position(obj.Pos()), iface.Filename, iface.Line) {
+				return fmt.Errorf("type %s is not declared at package scope", name)
+			}
+
+			*target.cached = append(*target.cached, newCachedInterface(iface))
+		}
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := gcexportdata.Write(buffer, e.fset, pkg.Types); err != nil {
+		return err
+	}
+	entry.ExportData = buffer.Bytes()
+
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(e.cacheDir, os.ModePerm); err != nil {
+		return err
+	}
+
+	// Entries are written atomically so that concurrent runs never read partial ones.
+	tempFile, err := os.CreateTemp(e.cacheDir, "."+key+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	tempName := tempFile.Name()
+	if _, err := tempFile.Write(content); err != nil {
+		_ = tempFile.Close()
+		_ = os.Remove(tempName)
+		return err
+	}
+
+	if err := tempFile.Close(); err != nil {
+		_ = os.Remove(tempName)
+		return err
+	}
+
+	if err := os.Rename(tempName, filepath.Join(e.cacheDir, key+".json")); err != nil {
+		_ = os.Remove(tempName)
+		return err
+	}
+
+	return nil
+}
+
+func newCachedInterface(iface *types.Interface) *cachedInterface {
+	methods := []*cachedMethod{}
+	for _, method := range iface.Methods {
+		methods = append(methods, &cachedMethod{
+			Name:       method.Name,
+			Doc:        method.Doc,
+			Directives: method.Directives,
+			Filename:   method.Filename,
+			Line:       method.Line,
+		})
+	}
+
+	return &cachedInterface{
+		Name:       iface.Name,
+		Doc:        iface.Doc,
+		Directives: iface.Directives,
+		Filename:   iface.Filename,
+		Line:       iface.Line,
+		Methods:    methods,
+	}
+}
+
+func samePosition(position token.Position, filename string, line int) bool {
+	return position.Filename == filename && position.Line == line
+}
+
+func sortedNames(ifaces map[string]*types.Interface) []string {
+	names := []string{}
+	for name := range ifaces {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+func newCacheKeys(packageConfig *gopackages.Config, testPackages TestPackages) *cacheKeys {
+	h := sha256.New()
+	fmt.Fprintf(h, "version %s\n", cacheVersion)
+	fmt.Fprintf(h, "tests %d\n", testPackages)
+
+	for _, flag := range packageConfig.BuildFlags {
+		fmt.Fprintf(h, "flag %s\n", flag)
+	}
+
+	env := packageConfig.Env
+	if env == nil {
+		env = os.Environ()
+	}
+
+	for _, v := range env {
+		if strings.HasPrefix(v, "GO") || strings.HasPrefix(v, "CGO_") {
+			fmt.Fprintf(h, "env %s\n", v)
+		}
+	}
+
+	return &cacheKeys{
+		base:    h.Sum(nil),
+		keys:    map[string]string{},
+		files:   map[string]string{},
+		overlay: packageConfig.Overlay,
+	}
+}
+
+// key hashes the files of the package and, recursively, the keys of its imports.
+func (k *cacheKeys) key(pkg *gopackages.Package) string {
+	if key, ok := k.keys[pkg.ID]; ok {
+		return key
+	}
+
+	h := sha256.New()
+	h.Write(k.base)
+	fmt.Fprintf(h, "id %s\n", pkg.ID)
+
+	// Files outside of the main module are identified by size and modification time.
+	inMainModule := pkg.Module != nil && pkg.Module.Main
+	if inMainModule && pkg.Module.GoMod != "" {
+		for _, filename := range []string{pkg.Module.GoMod, filepath.Join(filepath.Dir(pkg.Module.GoMod), "go.sum")} {
+			fmt.Fprintf(h, "module file %s %s\n", filename, k.fileDigest(filename, true))
+		}
+	}
+
+	for _, filename := range pkg.GoFiles {
+		fmt.Fprintf(h, "file %s %s\n", filename, k.fileDigest(filename, inMainModule))
+	}
+
+	importPaths := []string{}
+	for importPath := range pkg.Imports {
+		importPaths = append(importPaths, importPath)
+	}
+	sort.Strings(importPaths)
+
+	for _, importPath := range importPaths {
+		fmt.Fprintf(h, "import %s %s\n", importPath, k.key(pkg.Imports[importPath]))
+	}
+
+	key := hex.EncodeToString(h.Sum(nil))
+	k.keys[pkg.ID] = key
+	return key
+}
+
+func (k *cacheKeys) fileDigest(filename string, hashContent bool) string {
+	if digest, ok := k.files[filename]; ok {
+		return digest
+	}
+
+	// Overlaid files are read from memory instead of disk by go/packages.
+	digest := "missing"
+	if content, ok := k.overlay[filename]; ok {
+		sum := sha256.Sum256(content)
+		digest = "overlay " + hex.EncodeToString(sum[:])
+	} else if hashContent {
+		if h, err := hashFile(sha256.New(), filename); err == nil {
+			digest = hex.EncodeToString(h.Sum(nil))
+		}
+	} else if info, err := os.Stat(filename); err == nil {
+		digest = fmt.Sprintf("%d %d", info.Size(), info.ModTime().UnixNano())
+	}
+
+	k.files[filename] = digest
+	return digest
+}
+
+func hashFile(h hash.Hash, filename string) (hash.Hash, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
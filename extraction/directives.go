@@ -0,0 +1,45 @@
+package extraction
+
+import (
+	"go/ast"
+	"strings"
+
+	"github.com/efritz/go-genlib/types"
+)
+
+// parseDirectives parses `//genlib:<name> key=value ...` lines.
+func parseDirectives(doc *ast.CommentGroup) types.Directives {
+	directives := types.Directives{}
+	if doc == nil {
+		return directives
+	}
+
+	for _, comment := range doc.List {
+		text := strings.TrimPrefix(comment.Text, "//")
+		if !strings.HasPrefix(text, types.DirectivePrefix) {
+			continue
+		}
+
+		fields := strings.Fields(text[len(types.DirectivePrefix):])
+		if len(fields) == 0 {
+			continue
+		}
+
+		args, ok := directives[fields[0]]
+		if !ok {
+			args = map[string]string{}
+			directives[fields[0]] = args
+		}
+
+		for _, field := range fields[1:] {
+			parts := strings.SplitN(field, "=", 2)
+			if len(parts) == 1 {
+				parts = append(parts, "")
+			}
+
+			args[parts[0]] = parts[1]
+		}
+	}
+
+	return directives
+}
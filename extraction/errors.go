@@ -0,0 +1,118 @@
+package extraction
+
+import (
+	"fmt"
+	"strings"
+
+	gopackages "golang.org/x/tools/go/packages"
+)
+
+type ErrorKind int
+
+const (
+	UnknownError ErrorKind = iota
+	ListError
+	ParseError
+	TypeError
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case ListError:
+		return "list error"
+	case ParseError:
+		return "parse error"
+	case TypeError:
+		return "type error"
+	}
+
+	return "unknown error"
+}
+
+// PackageError is an error reported while loading a package.
+type PackageError struct {
+	ImportPath string
+	Pos        string
+	Msg        string
+	Kind       ErrorKind
+}
+
+func (e *PackageError) Error() string {
+	if e.Pos == "" || e.Pos == "-" {
+		return fmt.Sprintf("%s: %s (%s)", e.ImportPath, e.Msg, e.Kind)
+	}
+
+	return fmt.Sprintf("%s: %s: %s (%s)", e.ImportPath, e.Pos, e.Msg, e.Kind)
+}
+
+// PackageErrors is the set of every error reported while loading packages.
+type PackageErrors []*PackageError
+
+func (e PackageErrors) Error() string {
+	messages := []string{}
+	for _, err := range e {
+		messages = append(messages, "  "+err.Error())
+	}
+
+	return fmt.Sprintf("malformed packages:\n%s", strings.Join(messages, "\n"))
+}
+
+func (e PackageErrors) Unwrap() []error {
+	errs := []error{}
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+func collectPackageErrors(pkgs []*gopackages.Package) PackageErrors {
+	errs := PackageErrors{}
+	seen := map[string]struct{}{}
+
+	for _, pkg := range pkgs {
+		hasSyntaxErrors := false
+		for _, err := range pkg.Errors {
+			if err.Kind == gopackages.ParseError || err.Kind == gopackages.TypeError {
+				hasSyntaxErrors = true
+			}
+		}
+
+		for _, err := range pkg.Errors {
+			// Compiler output reported by go list duplicates the parse
+			// and type errors reported for the same package.
+			if hasSyntaxErrors && err.Kind == gopackages.ListError && strings.HasPrefix(err.Msg, "# ") {
+				continue
+			}
+
+			packageError := &PackageError{
+				ImportPath: pkg.PkgPath,
+				Pos:        err.Pos,
+				Msg:        err.Msg,
+				Kind:       convertErrorKind(err.Kind),
+			}
+
+			if _, ok := seen[packageError.Error()]; ok {
+				continue
+			}
+
+			seen[packageError.Error()] = struct{}{}
+			errs = append(errs, packageError)
+		}
+	}
+
+	return errs
+}
+
+func convertErrorKind(kind gopackages.ErrorKind) ErrorKind {
+	switch kind {
+	case gopackages.ListError:
+		return ListError
+	case gopackages.ParseError:
+		return ParseError
+	case gopackages.TypeError:
+		return TypeError
+	}
+
+	return UnknownError
+}
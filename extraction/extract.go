@@ -1,13 +1,13 @@
 package extraction
 
 import (
+	"context"
 	"fmt"
 	"go/ast"
-	"go/importer"
 	"go/token"
-	gotypes "go/types"
 	"log"
 	"os"
+	"strings"
 
 	gopackages "golang.org/x/tools/go/packages"
 
@@ -16,62 +16,233 @@ import (
 )
 
 type Extractor struct {
-	workingDirectory string
-	fset             *token.FileSet
-	typeConfig       gotypes.Config
+	workingDirectory    string
+	logger              Logger
+	packagesConfigFuncs []func(config *gopackages.Config)
+	fset                *token.FileSet
+	lenient             bool
+	testPackages        TestPackages
+	buildTags           []string
+	goos                string
+	goarch              string
+	buildFlags          []string
+	cacheDir            string
+	warnings            []*Warning
 }
 
-func NewExtractor() (*Extractor, error) {
-	workingDirectory, err := os.Getwd()
+func NewExtractor(configs ...ConfigFunc) (*Extractor, error) {
+	extractor := &Extractor{
+		logger: log.Default(),
+		fset:   token.NewFileSet(),
+	}
+
+	for _, f := range configs {
+		f(extractor)
+	}
+
+	if extractor.workingDirectory == "" {
+		workingDirectory, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get working directory (%s)", err.Error())
+		}
+
+		extractor.workingDirectory = workingDirectory
+	}
+
+	return extractor, nil
+}
+
+// Warnings returns the package errors tolerated so far by a lenient extractor.
+func (e *Extractor) Warnings() []*Warning {
+	return e.warnings
+}
+
+// Extract loads the packages matching the given import paths or patterns, keyed
+// by package ID. Packages are loaded anew on every call.
+func (e *Extractor) Extract(ctx context.Context, importPaths []string) (*types.Packages, error) {
+	pkgs, err := e.ExtractBatch(ctx, [][]string{importPaths})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get working directory (%s)", err.Error())
+		return nil, err
 	}
 
-	return &Extractor{
-		workingDirectory: workingDirectory,
-		fset:             token.NewFileSet(),
-		typeConfig:       gotypes.Config{Importer: importer.For("source", nil)},
-	}, nil
+	return pkgs[0], nil
 }
 
-func (e *Extractor) Extract(importPaths []string) (*types.Packages, error) {
-	packageConfig := &gopackages.Config{
-		Mode: gopackages.LoadSyntax,
+// ExtractBatch is like Extract but loads every batch with a single call to
+// go/packages, returning the packages of each batch in order.
+func (e *Extractor) ExtractBatch(ctx context.Context, batches [][]string) ([]*types.Packages, error) {
+	importPaths := []string{}
+	seen := map[string]struct{}{}
+	for _, batch := range batches {
+		for _, importPath := range batch {
+			if _, ok := seen[importPath]; !ok {
+				seen[importPath] = struct{}{}
+				importPaths = append(importPaths, importPath)
+			}
+		}
+	}
+
+	loaded, err := e.load(ctx, importPaths)
+	if err != nil {
+		return nil, err
 	}
 
-	packages := map[string]*types.Package{}
+	allPackages := []*types.Packages{}
+	for _, batch := range batches {
+		packages := map[string]*types.Package{}
+		for _, importPath := range batch {
+			for _, pkg := range loaded[importPath] {
+				packages[pkg.ID] = pkg
+			}
+		}
+
+		allPackages = append(allPackages, types.NewPackages(packages))
+	}
+
+	return allPackages, nil
+}
+
+func (e *Extractor) load(ctx context.Context, importPaths []string) (map[string][]*types.Package, error) {
 	for _, importPath := range importPaths {
-		path, dir := paths.ResolveImportPath(e.workingDirectory, importPath)
+		_, dir := paths.ResolveImportPath(e.workingDirectory, importPath)
 
-		log.Printf(
+		e.logger.Printf(
 			"parsing package '%s'\n",
-			paths.GetRelativePath(dir),
+			paths.GetRelativePathFrom(e.workingDirectory, dir),
 		)
+	}
 
-		pkgs, err := gopackages.Load(packageConfig, importPath)
-		if err != nil {
-			return nil, fmt.Errorf(
-				"could not load package %s (%s)",
-				importPath,
-				err.Error(),
-			)
+	var keys map[string]string
+	if e.cacheDir != "" {
+		loaded, cacheKeys, err := e.loadCached(ctx, importPaths)
+		if err != nil || loaded != nil {
+			return loaded, err
+		}
+
+		keys = cacheKeys
+	}
+
+	pkgs, err := gopackages.Load(e.packagesConfig(ctx, gopackages.LoadSyntax|gopackages.NeedForTest), importPaths...)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not load packages %s (%s)",
+			strings.Join(importPaths, ", "),
+			err.Error(),
+		)
+	}
+
+	if errs := collectPackageErrors(pkgs); len(errs) > 0 && !e.lenient {
+		return nil, errs
+	}
+
+	pkgs = e.testPackages.selectPackages(pkgs)
+
+	extracted := map[*gopackages.Package]*types.Package{}
+	for _, pkg := range pkgs {
+		errs := collectPackageErrors([]*gopackages.Package{pkg})
+
+		// Lenient extraction requires at least partial syntax and type information.
+		if len(errs) > 0 && (pkg.Types == nil || len(pkg.Syntax) == 0) {
+			return nil, errs
 		}
 
-		for _, err := range pkgs[0].Errors {
-			return nil, fmt.Errorf(
-				"malformed package %s (%s)",
-				importPath,
-				err.Msg,
-			)
+		extracted[pkg] = e.extractPackage(pkg, errs)
+
+		if key, ok := keys[pkg.ID]; ok && len(errs) == 0 {
+			if err := e.writeCacheEntry(key, pkg, extracted[pkg]); err != nil {
+				e.logger.Printf("warning: could not cache package %s (%s)\n", pkg.ID, err.Error())
+			}
 		}
+	}
+
+	return e.assignPackages(importPaths, pkgs, extracted)
+}
 
-		visitor := newVisitor(path, pkgs[0].Types)
-		for _, file := range pkgs[0].Syntax {
-			ast.Walk(visitor, file)
+func (e *Extractor) packagesConfig(ctx context.Context, mode gopackages.LoadMode) *gopackages.Config {
+	packageConfig := &gopackages.Config{
+		Context:    ctx,
+		Mode:       mode,
+		Dir:        e.workingDirectory,
+		Env:        e.env(),
+		BuildFlags: e.allBuildFlags(),
+		Tests:      e.testPackages != TestPackagesNone,
+		Fset:       e.fset,
+	}
+
+	for _, f := range e.packagesConfigFuncs {
+		f(packageConfig)
+	}
+
+	return packageConfig
+}
+
+// assignPackages returns the packages matching each of the given import paths.
+func (e *Extractor) assignPackages(
+	importPaths []string,
+	pkgs []*gopackages.Package,
+	extracted map[*gopackages.Package]*types.Package,
+) (map[string][]*types.Package, error) {
+	allLoaded := map[string][]*types.Package{}
+	for _, importPath := range importPaths {
+		loaded := []*types.Package{}
+		for _, pkg := range pkgs {
+			if len(importPaths) == 1 || matchesPattern(importPath, e.workingDirectory, pkg) {
+				loaded = append(loaded, extracted[pkg])
+			}
 		}
 
-		packages[path] = types.NewPackage(path, visitor.types)
+		if len(loaded) == 0 {
+			return nil, fmt.Errorf("no packages matched %s", importPath)
+		}
+
+		allLoaded[importPath] = loaded
+	}
+
+	return allLoaded, nil
+}
+
+func (e *Extractor) env() []string {
+	env := os.Environ()
+	if e.goos != "" {
+		env = append(env, "GOOS="+e.goos)
+	}
+
+	if e.goarch != "" {
+		env = append(env, "GOARCH="+e.goarch)
+	}
+
+	return env
+}
+
+func (e *Extractor) allBuildFlags() []string {
+	flags := []string{}
+	if len(e.buildTags) > 0 {
+		flags = append(flags, "-tags="+strings.Join(e.buildTags, ","))
+	}
+
+	return append(flags, e.buildFlags...)
+}
+
+func (e *Extractor) extractPackage(pkg *gopackages.Package, errs PackageErrors) *types.Package {
+	visitor := newVisitor(pkg.PkgPath, pkg.Fset, pkg.Types, pkg.Syntax, errs)
+	for _, file := range pkg.Syntax {
+		ast.Walk(visitor, file)
+	}
+
+	for _, warning := range visitor.warnings() {
+		e.logger.Printf("warning: %s\n", warning.Error())
+		e.warnings = append(e.warnings, warning)
 	}
 
-	return types.NewPackages(packages), nil
+	return types.NewPackage(
+		pkg.ID,
+		pkg.PkgPath,
+		visitor.types,
+		visitor.methodSets,
+		visitor.pointerMethodSets,
+	)
 }
@@ -0,0 +1,228 @@
+package extraction
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	gotypes "go/types"
+	"strconv"
+	"strings"
+
+	"github.com/efritz/go-genlib/types"
+)
+
+// Warning is a package error tolerated by a lenient extractor. Declaration is
+// the name of the type skipped because of the error, if any.
+type Warning struct {
+	*PackageError
+	Declaration string
+}
+
+func (w *Warning) Error() string {
+	if w.Declaration == "" {
+		return w.PackageError.Error()
+	}
+
+	return fmt.Sprintf("%s (skipped type %s)", w.PackageError.Error(), w.Declaration)
+}
+
+type positionedError struct {
+	err         *PackageError
+	filename    string
+	line        int
+	column      int
+	declaration string
+}
+
+func newPositionedErrors(errs PackageErrors) []*positionedError {
+	positioned := []*positionedError{}
+	for _, err := range errs {
+		filename, line, column := parseErrorPosition(err.Pos)
+
+		positioned = append(positioned, &positionedError{
+			err:      err,
+			filename: filename,
+			line:     line,
+			column:   column,
+		})
+	}
+
+	return positioned
+}
+
+// parseErrorPosition splits a position of the form file[:line[:column]].
+func parseErrorPosition(pos string) (string, int, int) {
+	numbers := []int{}
+	for len(numbers) < 2 {
+		index := strings.LastIndex(pos, ":")
+		if index < 0 {
+			break
+		}
+
+		n, err := strconv.Atoi(pos[index+1:])
+		if err != nil {
+			break
+		}
+
+		numbers = append([]int{n}, numbers...)
+		pos = pos[:index]
+	}
+
+	for len(numbers) < 2 {
+		numbers = append(numbers, 0)
+	}
+
+	return pos, numbers[0], numbers[1]
+}
+
+// claimErrors attributes the errors within the range to the named declaration.
+func (v *visitor) claimErrors(name string, start, end token.Pos) bool {
+	var (
+		from    = v.fset.Position(start)
+		to      = v.fset.Position(end)
+		claimed = false
+	)
+
+	for _, err := range v.errors {
+		if err.filename != from.Filename {
+			continue
+		}
+
+		// Errors without a column apply to their entire line.
+		column := err.column
+		if column == 0 && err.line == from.Line {
+			column = from.Column
+		}
+
+		if comparePosition(err.line, column, from.Line, from.Column) < 0 {
+			continue
+		}
+
+		if comparePosition(err.line, column, to.Line, to.Column) > 0 {
+			continue
+		}
+
+		err.declaration = name
+		claimed = true
+	}
+
+	return claimed
+}
+
+func (v *visitor) claimMethodErrors(name string) bool {
+	claimed := false
+	for _, decl := range v.methodDecls[name] {
+		if v.claimErrors(name, decl.Pos(), decl.Type.End()) {
+			claimed = true
+		}
+	}
+
+	return claimed
+}
+
+func (v *visitor) skipInvalid(iface *types.Interface) bool {
+	for _, method := range iface.Methods {
+		for _, typ := range append(append([]gotypes.Type{}, method.Params...), method.Results...) {
+			if !containsInvalidType(typ) {
+				continue
+			}
+
+			v.errors = append(v.errors, &positionedError{
+				err: &PackageError{
+					ImportPath: v.importPath,
+					Pos:        fmt.Sprintf("%s:%d", iface.Filename, iface.Line),
+					Msg:        fmt.Sprintf("method %s refers to an invalid type", method.Name),
+					Kind:       TypeError,
+				},
+				declaration: iface.Name,
+			})
+
+			return true
+		}
+	}
+
+	return false
+}
+
+func (v *visitor) warnings() []*Warning {
+	warnings := []*Warning{}
+	for _, err := range v.errors {
+		warnings = append(warnings, &Warning{
+			PackageError: err.err,
+			Declaration:  err.declaration,
+		})
+	}
+
+	return warnings
+}
+
+func collectMethodDecls(files []*ast.File) map[string][]*ast.FuncDecl {
+	methodDecls := map[string][]*ast.FuncDecl{}
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Recv != nil && len(funcDecl.Recv.List) > 0 {
+				if name := receiverTypeName(funcDecl.Recv.List[0].Type); name != "" {
+					methodDecls[name] = append(methodDecls[name], funcDecl)
+				}
+			}
+		}
+	}
+
+	return methodDecls
+}
+
+func receiverTypeName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.StarExpr:
+		return receiverTypeName(e.X)
+	case *ast.ParenExpr:
+		return receiverTypeName(e.X)
+	case *ast.IndexExpr:
+		return receiverTypeName(e.X)
+	case *ast.IndexListExpr:
+		return receiverTypeName(e.X)
+	}
+
+	return ""
+}
+
+func containsInvalidType(typ gotypes.Type) bool {
+	switch t := typ.(type) {
+	case *gotypes.Basic:
+		return t.Kind() == gotypes.Invalid
+	case *gotypes.Array:
+		return containsInvalidType(t.Elem())
+	case *gotypes.Chan:
+		return containsInvalidType(t.Elem())
+	case *gotypes.Map:
+		return containsInvalidType(t.Key()) || containsInvalidType(t.Elem())
+	case *gotypes.Pointer:
+		return containsInvalidType(t.Elem())
+	case *gotypes.Slice:
+		return containsInvalidType(t.Elem())
+	case *gotypes.Signature:
+		for i := 0; i < t.Params().Len(); i++ {
+			if containsInvalidType(t.Params().At(i).Type()) {
+				return true
+			}
+		}
+
+		for i := 0; i < t.Results().Len(); i++ {
+			if containsInvalidType(t.Results().At(i).Type()) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func comparePosition(line1, column1, line2, column2 int) int {
+	if line1 != line2 {
+		return line1 - line2
+	}
+
+	return column1 - column2
+}
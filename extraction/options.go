@@ -0,0 +1,69 @@
+package extraction
+
+import (
+	gopackages "golang.org/x/tools/go/packages"
+)
+
+type ConfigFunc func(*Extractor)
+
+// Logger receives the progress messages and warnings of an extractor. It is
+// satisfied by *log.Logger.
+type Logger interface {
+	Printf(format string, args ...interface{})
+}
+
+// WithWorkingDirectory sets the directory from which relative package patterns
+// are resolved. The current working directory is used by default.
+func WithWorkingDirectory(dir string) ConfigFunc {
+	return func(e *Extractor) { e.workingDirectory = dir }
+}
+
+// WithLogger sets the destination of progress messages and warnings. The
+// standard logger is used by default.
+func WithLogger(logger Logger) ConfigFunc {
+	return func(e *Extractor) { e.logger = logger }
+}
+
+// WithPackagesConfig registers a function that may modify the configuration
+// passed to go/packages after all other options have been applied.
+func WithPackagesConfig(f func(config *gopackages.Config)) ConfigFunc {
+	return func(e *Extractor) { e.packagesConfigFuncs = append(e.packagesConfigFuncs, f) }
+}
+
+// WithLenient tolerates package errors. Types whose declarations contain errors
+// are skipped and every error is reported as a warning instead.
+func WithLenient(lenient bool) ConfigFunc {
+	return func(e *Extractor) { e.lenient = lenient }
+}
+
+// WithTestPackages includes the _test.go files of each package as determined
+// by the given mode.
+func WithTestPackages(mode TestPackages) ConfigFunc {
+	return func(e *Extractor) { e.testPackages = mode }
+}
+
+// WithBuildTags sets the build tags used to select the files of each package.
+func WithBuildTags(tags []string) ConfigFunc {
+	return func(e *Extractor) { e.buildTags = tags }
+}
+
+// WithGOOS sets the target operating system used to select the files of each package.
+func WithGOOS(goos string) ConfigFunc {
+	return func(e *Extractor) { e.goos = goos }
+}
+
+// WithGOARCH sets the target architecture used to select the files of each package.
+func WithGOARCH(goarch string) ConfigFunc {
+	return func(e *Extractor) { e.goarch = goarch }
+}
+
+// WithBuildFlags sets additional flags passed to the underlying build tool.
+func WithBuildFlags(flags []string) ConfigFunc {
+	return func(e *Extractor) { e.buildFlags = flags }
+}
+
+// WithCacheDir reuses the types extracted from unchanged packages stored in the
+// given directory. Caching is disabled by default.
+func WithCacheDir(dir string) ConfigFunc {
+	return func(e *Extractor) { e.cacheDir = dir }
+}
@@ -0,0 +1,61 @@
+package extraction
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	gopackages "golang.org/x/tools/go/packages"
+)
+
+// matchesPattern determines if the package was loaded because of the given pattern.
+func matchesPattern(pattern, workingDirectory string, pkg *gopackages.Package) bool {
+	if testedPath(pkg) == pattern {
+		return true
+	}
+
+	if isFilesystemPattern(pattern) {
+		if pkg.Dir == "" {
+			return false
+		}
+
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(workingDirectory, pattern)
+		}
+
+		return compilePattern(filepath.ToSlash(evalSymlinks(pattern))).MatchString(filepath.ToSlash(evalSymlinks(pkg.Dir)))
+	}
+
+	return compilePattern(pattern).MatchString(testedPath(pkg))
+}
+
+func isFilesystemPattern(pattern string) bool {
+	return filepath.IsAbs(pattern) || pattern == "." || pattern == ".." ||
+		strings.HasPrefix(pattern, "./") || strings.HasPrefix(pattern, "../")
+}
+
+// compilePattern converts a package pattern into a regular expression.
+func compilePattern(pattern string) *regexp.Regexp {
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.Replace(expr, `\.\.\.`, `.*`, -1)
+
+	if strings.HasSuffix(expr, `/.*`) {
+		expr = strings.TrimSuffix(expr, `/.*`) + `(/.*)?`
+	}
+
+	return regexp.MustCompile("^" + expr + "$")
+}
+
+// evalSymlinks resolves symlinks in the longest existing prefix of the path.
+func evalSymlinks(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+
+	dir, file := filepath.Split(path)
+	if dir == "" || filepath.Clean(dir) == path {
+		return path
+	}
+
+	return filepath.Join(evalSymlinks(filepath.Clean(dir)), file)
+}
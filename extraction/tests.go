@@ -0,0 +1,117 @@
+package extraction
+
+import (
+	"fmt"
+	"strings"
+
+	gopackages "golang.org/x/tools/go/packages"
+)
+
+// TestPackages determines which variants of each package are extracted.
+type TestPackages int
+
+const (
+	// TestPackagesNone extracts each package without its _test.go files.
+	TestPackagesNone TestPackages = iota
+
+	// TestPackagesInternal extracts each package along with the _test.go files
+	// declared in the same package.
+	TestPackagesInternal
+
+	// TestPackagesExternal extracts only the external `_test` packages.
+	TestPackagesExternal
+
+	// TestPackagesAll extracts each package along with its in-package _test.go
+	// files as well as its external `_test` package.
+	TestPackagesAll
+)
+
+var testPackagesNames = map[string]TestPackages{
+	"none":     TestPackagesNone,
+	"internal": TestPackagesInternal,
+	"external": TestPackagesExternal,
+	"all":      TestPackagesAll,
+}
+
+// ParseTestPackages converts one of none, internal, external, or all into a
+// TestPackages value.
+func ParseTestPackages(name string) (TestPackages, error) {
+	if name == "" {
+		return TestPackagesNone, nil
+	}
+
+	if mode, ok := testPackagesNames[name]; ok {
+		return mode, nil
+	}
+
+	return TestPackagesNone, fmt.Errorf("unknown test package mode '%s'", name)
+}
+
+func (m TestPackages) includesInternal() bool {
+	return m == TestPackagesInternal || m == TestPackagesAll
+}
+
+func (m TestPackages) includesExternal() bool {
+	return m == TestPackagesExternal || m == TestPackagesAll
+}
+
+// selectPackages keeps one variant of each package and drops test mains.
+func (m TestPackages) selectPackages(pkgs []*gopackages.Package) []*gopackages.Package {
+	if m == TestPackagesNone {
+		return pkgs
+	}
+
+	hasInternalVariant := map[string]bool{}
+	for _, pkg := range pkgs {
+		if isInternalTestVariant(pkg) {
+			hasInternalVariant[pkg.PkgPath] = true
+		}
+	}
+
+	selected := []*gopackages.Package{}
+	for _, pkg := range pkgs {
+		switch {
+		case isTestMain(pkg):
+			continue
+
+		case isExternalTestVariant(pkg):
+			if !m.includesExternal() {
+				continue
+			}
+
+		case isInternalTestVariant(pkg):
+			if !m.includesInternal() {
+				continue
+			}
+
+		default:
+			if !m.includesInternal() || hasInternalVariant[pkg.PkgPath] {
+				continue
+			}
+		}
+
+		selected = append(selected, pkg)
+	}
+
+	return selected
+}
+
+func isTestMain(pkg *gopackages.Package) bool {
+	return pkg.ForTest == "" && pkg.Name == "main" && strings.HasSuffix(pkg.ID, ".test")
+}
+
+func isInternalTestVariant(pkg *gopackages.Package) bool {
+	return pkg.ForTest != "" && pkg.PkgPath == pkg.ForTest
+}
+
+func isExternalTestVariant(pkg *gopackages.Package) bool {
+	return pkg.ForTest != "" && pkg.PkgPath == pkg.ForTest+"_test"
+}
+
+func testedPath(pkg *gopackages.Package) string {
+	if pkg.ForTest != "" {
+		return pkg.ForTest
+	}
+
+	return pkg.PkgPath
+}
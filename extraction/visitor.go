@@ -4,30 +4,58 @@ import (
 	"go/ast"
 	"go/token"
 	gotypes "go/types"
+	"strings"
 
 	"github.com/efritz/go-genlib/types"
 )
 
 type visitor struct {
-	importPath string
-	pkgType    *gotypes.Package
-	types      map[string]*types.Interface
+	importPath        string
+	fset              *token.FileSet
+	pkgType           *gotypes.Package
+	docs              map[token.Pos]*ast.CommentGroup
+	methodDecls       map[string][]*ast.FuncDecl
+	errors            []*positionedError
+	types             map[string]*types.Interface
+	methodSets        map[string]*types.Interface
+	pointerMethodSets map[string]*types.Interface
 }
 
-func newVisitor(importPath string, pkgType *gotypes.Package) *visitor {
+func newVisitor(
+	importPath string,
+	fset *token.FileSet,
+	pkgType *gotypes.Package,
+	files []*ast.File,
+	errs PackageErrors,
+) *visitor {
 	return &visitor{
-		importPath: importPath,
-		pkgType:    pkgType,
-		types:      map[string]*types.Interface{},
+		importPath:        importPath,
+		fset:              fset,
+		pkgType:           pkgType,
+		docs:              collectDocs(files),
+		methodDecls:       collectMethodDecls(files),
+		errors:            newPositionedErrors(errs),
+		types:             map[string]*types.Interface{},
+		methodSets:        map[string]*types.Interface{},
+		pointerMethodSets: map[string]*types.Interface{},
 	}
 }
 
 func (v *visitor) Visit(node ast.Node) ast.Visitor {
 	switch n := node.(type) {
+	case *ast.FuncDecl, *ast.FuncLit:
+		// Types declared within function bodies cannot be referenced by generated code.
+		return nil
+
 	case *ast.GenDecl:
 		for _, spec := range n.Specs {
 			if typeSpec, ok := spec.(*ast.TypeSpec); ok {
-				v.deconstructTypeSpec(typeSpec)
+				doc := typeSpec.Doc
+				if doc == nil && len(n.Specs) == 1 {
+					doc = n.Doc
+				}
+
+				v.deconstructTypeSpec(typeSpec, doc)
 			}
 		}
 	}
@@ -35,18 +63,150 @@ func (v *visitor) Visit(node ast.Node) ast.Visitor {
 	return v
 }
 
-func (v *visitor) deconstructTypeSpec(typeSpec *ast.TypeSpec) {
+func (v *visitor) deconstructTypeSpec(typeSpec *ast.TypeSpec, doc *ast.CommentGroup) {
 	name := typeSpec.Name.Name
+	typ := getType(v.pkgType, name, typeSpec.Pos())
+	position := v.fset.Position(typeSpec.Name.Pos())
+
+	if typ == nil || v.claimErrors(name, typeSpec.Pos(), typeSpec.End()) {
+		return
+	}
 
-	switch t := getUnderlyingType(v.pkgType, name, typeSpec.Pos()).(type) {
+	annotate := func(iface *types.Interface, typ gotypes.Type) *types.Interface {
+		iface.Doc = getText(doc)
+		iface.Directives = parseDirectives(doc)
+		iface.Filename = position.Filename
+		iface.Line = position.Line
+		v.annotateMethods(iface, typ)
+		return iface
+	}
+
+	var iface *types.Interface
+	switch t := typ.Underlying().(type) {
 	case *gotypes.Struct:
-		v.types[name] = types.DeconstructStruct(name, v.importPath, t)
+		iface = annotate(types.DeconstructStruct(name, v.importPath, t), typ)
 	case *gotypes.Interface:
-		v.types[name] = types.DeconstructInterface(name, v.importPath, t)
+		if t.IsMethodSet() {
+			iface = annotate(types.DeconstructInterface(name, v.importPath, t), typ)
+		}
+	}
+
+	typeParams := []*types.TypeParam{}
+	named, isNamed := typ.(*gotypes.Named)
+	if isNamed {
+		typeParams = types.DeconstructTypeParams(named.TypeParams())
+	}
+
+	if iface != nil && !v.skipInvalid(iface) {
+		iface.TypeParams = typeParams
+		v.types[name] = iface
+	}
+
+	if !isNamed || typeSpec.Assign.IsValid() {
+		return
+	}
+
+	switch named.Underlying().(type) {
+	case *gotypes.Interface, *gotypes.Pointer:
+		return
+	}
+
+	if v.claimMethodErrors(name) {
+		return
+	}
+
+	// Instantiating with its own type parameters rewrites the method signatures
+	// in terms of the type parameters of the declaration.
+	instance := instantiateWithTypeParams(named)
+
+	if iface := types.DeconstructMethodSet(name, v.importPath, instance); len(iface.Methods) > 0 {
+		if iface = annotate(iface, instance); !v.skipInvalid(iface) {
+			iface.TypeParams = typeParams
+			v.methodSets[name] = iface
+		}
+	}
+
+	if iface := types.DeconstructMethodSet(name, v.importPath, gotypes.NewPointer(instance)); len(iface.Methods) > 0 {
+		if iface = annotate(iface, gotypes.NewPointer(instance)); !v.skipInvalid(iface) {
+			iface.TypeParams = typeParams
+			v.pointerMethodSets[name] = iface
+		}
+	}
+}
+
+func (v *visitor) annotateMethods(iface *types.Interface, typ gotypes.Type) {
+	for _, method := range iface.Methods {
+		obj, _, _ := gotypes.LookupFieldOrMethod(typ, true, v.pkgType, method.Name)
+		if obj == nil {
+			continue
+		}
+
+		position := v.fset.Position(obj.Pos())
+		method.Doc = getText(v.docs[obj.Pos()])
+		method.Directives = parseDirectives(v.docs[obj.Pos()])
+		method.Filename = position.Filename
+		method.Line = position.Line
+	}
+}
+
+func getType(pkgType *gotypes.Package, name string, pos token.Pos) gotypes.Type {
+	scope := pkgType.Scope().Innermost(pos)
+	if scope == nil {
+		return nil
+	}
+
+	_, obj := scope.LookupParent(name, 0)
+	if obj == nil {
+		return nil
+	}
+
+	return obj.Type()
+}
+
+func instantiateWithTypeParams(named *gotypes.Named) gotypes.Type {
+	typeParams := named.TypeParams()
+	if typeParams.Len() == 0 {
+		return named
 	}
+
+	typeArgs := []gotypes.Type{}
+	for i := 0; i < typeParams.Len(); i++ {
+		typeArgs = append(typeArgs, typeParams.At(i))
+	}
+
+	instance, err := gotypes.Instantiate(nil, named, typeArgs, false)
+	if err != nil {
+		return named
+	}
+
+	return instance
+}
+
+func collectDocs(files []*ast.File) map[token.Pos]*ast.CommentGroup {
+	docs := map[token.Pos]*ast.CommentGroup{}
+	for _, file := range files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch n := node.(type) {
+			case *ast.Field:
+				for _, name := range n.Names {
+					if n.Doc != nil {
+						docs[name.Pos()] = n.Doc
+					}
+				}
+
+			case *ast.FuncDecl:
+				if n.Doc != nil {
+					docs[n.Name.Pos()] = n.Doc
+				}
+			}
+
+			return true
+		})
+	}
+
+	return docs
 }
 
-func getUnderlyingType(pkgType *gotypes.Package, name string, pos token.Pos) gotypes.Type {
-	_, obj := pkgType.Scope().Innermost(pos).LookupParent(name, 0)
-	return obj.Type().Underlying()
+func getText(doc *ast.CommentGroup) string {
+	return strings.TrimSpace(doc.Text())
 }
@@ -0,0 +1,51 @@
+package generation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/efritz/go-genlib/paths"
+)
+
+// checkFiles prints a diff of each stale file and returns an error listing them.
+func checkFiles(outputs []*output, prunable []string) error {
+	stale := []string{}
+	missing := []string{}
+
+	for _, output := range outputs {
+		relativePath := paths.GetRelativePath(output.filename)
+
+		if output.status == statusUnchanged {
+			continue
+		}
+
+		if output.status == statusCreate {
+			missing = append(missing, relativePath)
+			continue
+		}
+
+		if diff := unifiedDiff(relativePath, relativePath, output.previous, output.content); diff != "" {
+			fmt.Printf("%s", diff)
+			stale = append(stale, relativePath)
+		}
+	}
+
+	if len(stale) == 0 && len(missing) == 0 && len(prunable) == 0 {
+		return nil
+	}
+
+	lines := []string{}
+	for _, filename := range stale {
+		lines = append(lines, fmt.Sprintf("  stale:   %s", filename))
+	}
+
+	for _, filename := range missing {
+		lines = append(lines, fmt.Sprintf("  missing: %s", filename))
+	}
+
+	for _, filename := range prunable {
+		lines = append(lines, fmt.Sprintf("  extra:   %s", paths.GetRelativePath(filename)))
+	}
+
+	return fmt.Errorf("generated files are out of date:\n%s", strings.Join(lines, "\n"))
+}
@@ -0,0 +1,180 @@
+package generation
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+type edit struct {
+	kind byte
+	text string
+}
+
+const diffContext = 3
+
+func unifiedDiff(fromName, toName, from, to string) string {
+	if from == to {
+		return ""
+	}
+
+	edits := diffLines(splitLines(from), splitLines(to))
+
+	// Record the number of lines of each text preceding each edit.
+	aIndex := make([]int, len(edits)+1)
+	bIndex := make([]int, len(edits)+1)
+	for i, e := range edits {
+		aIndex[i+1], bIndex[i+1] = aIndex[i], bIndex[i]
+
+		if e.kind != '+' {
+			aIndex[i+1]++
+		}
+
+		if e.kind != '-' {
+			bIndex[i+1]++
+		}
+	}
+
+	buffer := &bytes.Buffer{}
+	fmt.Fprintf(buffer, "--- %s\n+++ %s\n", fromName, toName)
+
+	for i, floor := 0, 0; i < len(edits); {
+		for i < len(edits) && edits[i].kind == ' ' {
+			i++
+		}
+
+		if i == len(edits) {
+			break
+		}
+
+		end := i
+		for j := i; j < len(edits) && j-end <= 2*diffContext; j++ {
+			if edits[j].kind != ' ' {
+				end = j
+			}
+		}
+
+		start := max(i-diffContext, floor)
+		stop := min(end+diffContext+1, len(edits))
+
+		fmt.Fprintf(
+			buffer,
+			"@@ -%s +%s @@\n",
+			hunkRange(aIndex[start], aIndex[stop]-aIndex[start]),
+			hunkRange(bIndex[start], bIndex[stop]-bIndex[start]),
+		)
+
+		for _, e := range edits[start:stop] {
+			fmt.Fprintf(buffer, "%c%s", e.kind, e.text)
+
+			if !strings.HasSuffix(e.text, "\n") {
+				buffer.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+
+		i, floor = stop, stop
+	}
+
+	return buffer.String()
+}
+
+// diffLines computes a minimal edit script using Myers' algorithm.
+func diffLines(a, b []string) []edit {
+	var (
+		n      = len(a)
+		m      = len(b)
+		offset = n + m + 1
+		v      = make([]int, 2*offset+1)
+		trace  = [][]int{}
+	)
+
+search:
+	for d := 0; d <= n+m; d++ {
+		for k := -d; k <= d; k += 2 {
+			x := v[offset+k-1] + 1
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				break search
+			}
+		}
+
+		// Record the furthest reaching x on diagonals -d through d.
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+	}
+
+	edits := []edit{}
+	x, y := n, m
+
+	for d := len(trace); d > 0; d-- {
+		// The furthest reaching x on diagonal k after the previous step.
+		prev := func(k int) int { return trace[d-1][k+d-1] }
+
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && prev(k-1) < prev(k+1)) {
+			prevK = k + 1
+		}
+
+		prevX := prev(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			edits = append(edits, edit{' ', a[x-1]})
+			x--
+			y--
+		}
+
+		if x == prevX {
+			edits = append(edits, edit{'+', b[y-1]})
+			y--
+		} else {
+			edits = append(edits, edit{'-', a[x-1]})
+			x--
+		}
+	}
+
+	for x > 0 && y > 0 {
+		edits = append(edits, edit{' ', a[x-1]})
+		x--
+		y--
+	}
+
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+
+	return edits
+}
+
+// splitLines keeps the newlines so that a missing final newline is a difference.
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+func hunkRange(start, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+
+	if length == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+
+	return fmt.Sprintf("%d,%d", start+1, length)
+}
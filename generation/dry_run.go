@@ -0,0 +1,22 @@
+package generation
+
+import (
+	"fmt"
+
+	"github.com/efritz/go-genlib/paths"
+)
+
+func reportFiles(outputs []*output, prunable []string) {
+	for _, output := range outputs {
+		fmt.Printf(
+			"%-9s %s (%d bytes)\n",
+			output.status,
+			paths.GetRelativePath(output.filename),
+			len(output.content),
+		)
+	}
+
+	for _, filename := range prunable {
+		fmt.Printf("%-9s %s\n", statusDelete, paths.GetRelativePath(filename))
+	}
+}
@@ -2,9 +2,11 @@ package generation
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"log"
+	"os"
 	"path"
 	"path/filepath"
 	"strings"
@@ -17,19 +19,38 @@ import (
 
 type (
 	FilenameGenerator  func(name string) string
-	InterfaceGenerator func(file *jen.File, iface *types.Interface, prefix string)
+	InterfaceGenerator func(file *jen.File, iface *types.Interface, prefix string, importNames ImportNames)
+
+	// ImportNames maps the import path of each package referenced by the rendered
+	// interfaces to the name under which the file imports it.
+	ImportNames map[string]string
+
+	output struct {
+		filename string
+		content  string
+		status   string
+		previous string
+	}
 )
 
+// Generate renders the given interfaces and writes them to the output file or
+// directory described by opts.
 func Generate(
+	ctx context.Context,
 	appName string,
 	appVersion string,
 	ifaces []*types.Interface,
 	opts *command.Options,
 	filenameGenerator FilenameGenerator,
 	interfaceGenerator InterfaceGenerator,
-) error {
+) (*command.Result, error) {
+	if err := checkReferences(ifaces, opts.OutputImportPath); err != nil {
+		return nil, err
+	}
+
 	if opts.OutputFilename == "" && opts.OutputDir != "" {
 		return generateDirectory(
+			ctx,
 			appName,
 			appVersion,
 			ifaces,
@@ -39,110 +60,127 @@ func Generate(
 		)
 	}
 
-	return generateFile(appName, appVersion, ifaces, opts, interfaceGenerator)
+	return generateFile(ctx, appName, appVersion, ifaces, opts, interfaceGenerator)
 }
 
 func generateFile(
+	ctx context.Context,
 	appName string,
 	appVersion string,
 	ifaces []*types.Interface,
 	opts *command.Options,
 	interfaceGenerator InterfaceGenerator,
-) error {
+) (*command.Result, error) {
 	content, err := generateContent(
+		ctx,
 		appName,
 		appVersion,
 		ifaces,
-		opts.PkgName,
-		opts.Prefix,
+		opts,
 		interfaceGenerator,
 	)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if opts.OutputFilename != "" {
-		exists, err := paths.Exists(opts.OutputFilename)
-		if err != nil {
-			return err
-		}
+		filename := filepath.Join(opts.OutputDir, opts.OutputFilename)
 
-		if exists && !opts.Force {
-			return fmt.Errorf(
-				"filename %s already exists, overwrite with --force",
-				paths.GetRelativePath(opts.OutputFilename),
-			)
+		if !opts.Force && !opts.Check && !opts.DryRun {
+			exists, err := paths.Exists(filename)
+			if err != nil {
+				return nil, err
+			}
+
+			if exists {
+				return nil, fmt.Errorf(
+					"filename %s already exists, overwrite with --force",
+					paths.GetRelativePath(filename),
+				)
+			}
 		}
 
-		return writeFile(opts.OutputFilename, content)
+		return emitFiles([]*output{{filename: filename, content: content}}, nil, opts)
 	}
 
 	fmt.Printf("%s\n", content)
-	return nil
+	return &command.Result{}, nil
 }
 
 func generateDirectory(
+	ctx context.Context,
 	appName string,
 	appVersion string,
 	ifaces []*types.Interface,
 	opts *command.Options,
 	filenameGenerator FilenameGenerator,
 	interfaceGenerator InterfaceGenerator,
-) error {
+) (*command.Result, error) {
 	dirname := filepath.Join(opts.OutputDir, opts.OutputFilename)
 
-	if !opts.Force {
+	if !opts.Force && !opts.Check && !opts.DryRun {
 		allPaths := []string{}
 		for _, iface := range ifaces {
 			allPaths = append(allPaths, getFilename(
 				dirname,
 				iface.Name,
-				opts.Prefix,
+				getPrefix(iface, opts.Prefix),
 				filenameGenerator,
 			))
 		}
 
 		conflict, err := paths.AnyExists(allPaths)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		if conflict != "" {
-			return fmt.Errorf(
+			return nil, fmt.Errorf(
 				"filename %s already exists, overwrite with --force",
 				paths.GetRelativePath(conflict),
 			)
 		}
 	}
 
-	for _, iface := range ifaces {
+	outputs := make([]*output, len(ifaces))
+	for i, iface := range ifaces {
+		outputs[i] = &output{filename: getFilename(
+			dirname,
+			iface.Name,
+			getPrefix(iface, opts.Prefix),
+			filenameGenerator,
+		)}
+	}
+
+	// Each interface is rendered into its own file, so order does not matter.
+	if err := forEach(len(ifaces), opts.Jobs, func(i int) error {
 		content, err := generateContent(
+			ctx,
 			appName,
 			appVersion,
-			[]*types.Interface{iface},
-			opts.PkgName,
-			opts.Prefix,
+			[]*types.Interface{ifaces[i]},
+			opts,
 			interfaceGenerator,
 		)
 
+		outputs[i].content = content
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	prunable := []string{}
+	if opts.Prune {
+		filenames, err := findPrunableFiles(appName, dirname, outputs)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		filename := getFilename(
-			dirname,
-			iface.Name,
-			opts.Prefix,
-			filenameGenerator,
-		)
-
-		if err := writeFile(filename, content); err != nil {
-			return err
-		}
+		prunable = filenames
 	}
 
-	return nil
+	return emitFiles(outputs, prunable, opts)
 }
 
 func getFilename(dirname, interfaceName, prefix string, filenameGenerator FilenameGenerator) string {
@@ -154,23 +192,36 @@ func getFilename(dirname, interfaceName, prefix string, filenameGenerator Filena
 	return path.Join(dirname, strings.Replace(strings.ToLower(filename), "-", "_", -1))
 }
 
+func getPrefix(iface *types.Interface, prefix string) string {
+	if value, ok := iface.Directives.Get(types.GenerateDirective, "prefix"); ok {
+		return value
+	}
+
+	return prefix
+}
+
 func generateContent(
+	ctx context.Context,
 	appName string,
 	appVersion string,
 	ifaces []*types.Interface,
-	pkgName string,
-	prefix string,
+	opts *command.Options,
 	interfaceGenerator InterfaceGenerator,
 ) (string, error) {
-	file := newFile(appName, appVersion, pkgName)
+	file := newFile(appName, appVersion, opts.PkgName, getBuildConstraint(opts))
+	importNames := applyImportPolicy(file, ifaces, opts.OutputImportPath, opts.ImportAliases, opts.ReservedNames)
 
 	for _, iface := range ifaces {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+
 		log.Printf(
 			"generating code for interface '%s'\n",
 			iface.Name,
 		)
 
-		interfaceGenerator(file, iface, prefix)
+		interfaceGenerator(file, iface, getPrefix(iface, opts.Prefix), importNames)
 	}
 
 	buffer := &bytes.Buffer{}
@@ -181,17 +232,123 @@ func generateContent(
 	return buffer.String(), nil
 }
 
-func newFile(appName, appVersion, pkgName string) *jen.File {
+func newFile(appName, appVersion, pkgName, buildConstraint string) *jen.File {
 	file := jen.NewFile(pkgName)
-	file.HeaderComment(fmt.Sprintf("Code generated by %s %s; DO NOT EDIT.", appName, appVersion))
+	file.HeaderComment(fmt.Sprintf(generatedHeaderFormat, appName, appVersion))
+	if buildConstraint != "" {
+		file.HeaderComment("//go:build " + buildConstraint)
+	}
+
 	return file
 }
 
-func writeFile(filename, content string) error {
-	log.Printf(
-		"writing to '%s'\n",
-		paths.GetRelativePath(filename),
-	)
+// getBuildConstraint returns the //go:build expression matching the load options.
+func getBuildConstraint(opts *command.Options) string {
+	if !opts.BuildConstraint {
+		return ""
+	}
+
+	terms := append([]string{}, opts.BuildTags...)
+	for _, term := range []string{opts.GOOS, opts.GOARCH} {
+		if term != "" {
+			terms = append(terms, term)
+		}
+	}
+
+	return strings.Join(terms, " && ")
+}
+
+const (
+	statusCreate    = "create"
+	statusOverwrite = "overwrite"
+	statusUnchanged = "unchanged"
+	statusDelete    = "delete"
+	statusConflict  = "conflict"
+)
+
+func emitFiles(outputs []*output, prunable []string, opts *command.Options) (*command.Result, error) {
+	if err := forEach(len(outputs), opts.Jobs, func(i int) (err error) {
+		outputs[i].status, outputs[i].previous, err = getStatus(outputs[i])
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	// Without --force, a write would fail on any file that already exists.
+	if opts.DryRun && !opts.Force {
+		for _, output := range outputs {
+			if output.status != statusCreate {
+				output.status = statusConflict
+			}
+		}
+	}
+
+	result := &command.Result{Deleted: prunable}
+	for _, output := range outputs {
+		switch output.status {
+		case statusCreate:
+			result.Created = append(result.Created, output.filename)
+		case statusOverwrite:
+			result.Updated = append(result.Updated, output.filename)
+		case statusUnchanged:
+			result.Unchanged = append(result.Unchanged, output.filename)
+		case statusConflict:
+			result.Conflicts = append(result.Conflicts, output.filename)
+		}
+	}
+
+	if opts.Check {
+		if err := checkFiles(outputs, prunable); err != nil {
+			return nil, err
+		}
+
+		return result, nil
+	}
+
+	if opts.DryRun {
+		reportFiles(outputs, prunable)
+		return result, nil
+	}
+
+	changed := []*output{}
+	for _, output := range outputs {
+		if output.status != statusUnchanged {
+			changed = append(changed, output)
+		}
+	}
+
+	if opts.AllOrNothing {
+		if err := writeFilesAllOrNothing(changed); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := forEach(len(changed), opts.Jobs, func(i int) error {
+			return writeFile(changed[i].filename, changed[i].content)
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := pruneFiles(prunable); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func getStatus(output *output) (string, string, error) {
+	content, err := ioutil.ReadFile(output.filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return statusCreate, "", nil
+		}
+
+		return "", "", err
+	}
+
+	if string(content) == output.content {
+		return statusUnchanged, string(content), nil
+	}
 
-	return ioutil.WriteFile(filename, []byte(content), 0644)
+	return statusOverwrite, string(content), nil
 }
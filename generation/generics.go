@@ -0,0 +1,67 @@
+package generation
+
+import (
+	"github.com/dave/jennifer/jen"
+	"github.com/efritz/go-genlib/types"
+)
+
+// GenerateTypeParams generates a type parameter list such as `K comparable, V any`.
+func GenerateTypeParams(iface *types.Interface, outputImportPath string) []jen.Code {
+	typeParams := []jen.Code{}
+	for _, typeParam := range iface.TypeParams {
+		typeParams = append(typeParams, Compose(
+			jen.Id(typeParam.Name),
+			GenerateType(typeParam.Constraint, iface.ImportPath, outputImportPath, false),
+		))
+	}
+
+	return typeParams
+}
+
+// GenerateTypeArgs generates a type argument list such as `K, V`.
+func GenerateTypeArgs(iface *types.Interface) []jen.Code {
+	typeArgs := []jen.Code{}
+	for _, typeParam := range iface.TypeParams {
+		typeArgs = append(typeArgs, jen.Id(typeParam.Name))
+	}
+
+	return typeArgs
+}
+
+// GenerateGenericName generates an instantiated type name such as `MockStore[K, V]`.
+func GenerateGenericName(name string, iface *types.Interface) *jen.Statement {
+	return withTypes(jen.Id(name), GenerateTypeArgs(iface))
+}
+
+// GenerateGenericStruct generates a struct declaration parameterized like iface.
+func GenerateGenericStruct(name string, iface *types.Interface, outputImportPath string, fields ...jen.Code) jen.Code {
+	return withTypes(jen.Type().Id(name), GenerateTypeParams(iface, outputImportPath)).Struct(fields...)
+}
+
+// GenerateGenericReceiver generates a receiver such as `m *MockStore[K, V]`.
+func GenerateGenericReceiver(receiverName, typeName string, iface *types.Interface) jen.Code {
+	return jen.Id(receiverName).Op("*").Add(GenerateGenericName(typeName, iface))
+}
+
+// GenerateGenericFunction generates a function declaration parameterized like iface.
+func GenerateGenericFunction(
+	functionName string,
+	iface *types.Interface,
+	outputImportPath string,
+	params []jen.Code,
+	results []jen.Code,
+	body ...jen.Code,
+) jen.Code {
+	return withTypes(jen.Func().Id(functionName), GenerateTypeParams(iface, outputImportPath)).
+		Params(params...).
+		Params(results...).
+		Block(body...)
+}
+
+func withTypes(stmt *jen.Statement, types []jen.Code) *jen.Statement {
+	if len(types) == 0 {
+		return stmt
+	}
+
+	return stmt.Types(types...)
+}
@@ -0,0 +1,190 @@
+package generation
+
+import (
+	"go/token"
+	"go/types"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+	gentypes "github.com/efritz/go-genlib/types"
+)
+
+type importName struct {
+	name  string
+	alias bool
+}
+
+var (
+	majorVersionPattern = regexp.MustCompile(`^v[0-9]+$`)
+	nonAlphanumeric     = regexp.MustCompile(`[^a-z0-9]`)
+
+	// standardNames lists the top-level standard library packages as of Go 1.25.
+	standardNames = map[string]struct{}{
+		"archive": {}, "bufio": {}, "bytes": {}, "cmp": {}, "compress": {}, "container": {},
+		"context": {}, "crypto": {}, "database": {}, "debug": {}, "embed": {}, "encoding": {},
+		"errors": {}, "expvar": {}, "flag": {}, "fmt": {}, "go": {}, "hash": {}, "html": {},
+		"image": {}, "index": {}, "io": {}, "iter": {}, "log": {}, "maps": {}, "math": {},
+		"mime": {}, "net": {}, "os": {}, "path": {}, "plugin": {}, "reflect": {}, "regexp": {},
+		"runtime": {}, "slices": {}, "sort": {}, "strconv": {}, "strings": {}, "structs": {},
+		"sync": {}, "syscall": {}, "testing": {}, "text": {}, "time": {}, "unicode": {},
+		"unique": {}, "unsafe": {}, "weak": {},
+	}
+)
+
+// applyImportPolicy registers the import name of each referenced package.
+func applyImportPolicy(
+	file *jen.File,
+	ifaces []*gentypes.Interface,
+	outputImportPath string,
+	aliases map[string]string,
+	reserved []string,
+) ImportNames {
+	importNames := ImportNames{}
+	for path, name := range chooseImportNames(collectImports(ifaces, outputImportPath), aliases, reserved) {
+		if name.alias {
+			file.ImportAlias(path, name.name)
+		} else {
+			file.ImportName(path, name.name)
+		}
+
+		importNames[path] = name.name
+	}
+
+	return importNames
+}
+
+func collectImports(ifaces []*gentypes.Interface, outputImportPath string) map[string]string {
+	imports := map[string]string{}
+	for _, iface := range ifaces {
+		add := func(typ types.Type) {
+			walkObjects(typ, func(obj types.Object) {
+				if _, ok := obj.(*types.TypeName); !ok || obj.Pkg() == nil {
+					return
+				}
+
+				path := obj.Pkg().Path()
+				if path == "" {
+					path = iface.ImportPath
+				}
+
+				if path = SanitizeImportPath(path, outputImportPath); path != "" {
+					imports[path] = obj.Pkg().Name()
+				}
+			})
+		}
+
+		for _, typeParam := range iface.TypeParams {
+			add(typeParam.Constraint)
+		}
+
+		for _, method := range iface.Methods {
+			for _, typ := range append(append([]types.Type{}, method.Params...), method.Results...) {
+				add(typ)
+			}
+		}
+	}
+
+	return imports
+}
+
+func chooseImportNames(imports map[string]string, aliases map[string]string, reserved []string) map[string]importName {
+	taken := map[string]struct{}{}
+	for _, name := range reserved {
+		taken[name] = struct{}{}
+	}
+
+	for _, name := range types.Universe.Names() {
+		taken[name] = struct{}{}
+	}
+
+	names := map[string]importName{}
+	groups := map[string][]string{}
+
+	for _, path := range sortedKeys(imports) {
+		if alias, ok := aliases[path]; ok {
+			names[path] = importName{name: alias, alias: alias != imports[path]}
+			taken[alias] = struct{}{}
+			continue
+		}
+
+		groups[imports[path]] = append(groups[imports[path]], path)
+	}
+
+	for _, name := range sortedKeys(groups) {
+		paths := groups[name]
+		sort.SliceStable(paths, func(i, j int) bool {
+			return isStandardPath(paths[i]) && !isStandardPath(paths[j])
+		})
+
+		for i, path := range paths {
+			candidate := name
+
+			// A package outside of the standard library never takes the name of a
+			// standard library package, which the interface generator may import.
+			unique := len(paths) == 1 || (i == 0 && isStandardPath(path))
+			if !unique || !isAvailable(candidate, taken) || (!isStandardPath(path) && isStandardName(name)) {
+				candidate = qualifiedImportName(path, name, taken)
+			}
+
+			names[path] = importName{name: candidate, alias: candidate != name}
+			taken[candidate] = struct{}{}
+		}
+	}
+
+	return names
+}
+
+// qualifiedImportName prefixes the name with elements of its path (e.g. pkgerrors).
+func qualifiedImportName(path, name string, taken map[string]struct{}) string {
+	elements := strings.Split(path, "/")
+	if n := len(elements); n > 1 && majorVersionPattern.MatchString(elements[n-1]) {
+		elements = elements[:n-1]
+	}
+
+	base := sanitizeImportName(name)
+	candidate := base
+	for i := len(elements) - 2; i >= 0; i-- {
+		if prefix := sanitizeImportName(elements[i]); prefix != "" {
+			if candidate = prefix + candidate; isAvailable(candidate, taken) {
+				return candidate
+			}
+		}
+	}
+
+	for i := 2; ; i++ {
+		if candidate := base + strconv.Itoa(i); isAvailable(candidate, taken) {
+			return candidate
+		}
+	}
+}
+
+func sanitizeImportName(name string) string {
+	return strings.TrimLeft(nonAlphanumeric.ReplaceAllString(strings.ToLower(name), ""), "0123456789")
+}
+
+func isAvailable(name string, taken map[string]struct{}) bool {
+	_, ok := taken[name]
+	return !ok && !token.IsKeyword(name)
+}
+
+func isStandardPath(path string) bool {
+	return !strings.Contains(strings.Split(path, "/")[0], ".")
+}
+
+func isStandardName(name string) bool {
+	_, ok := standardNames[name]
+	return ok
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
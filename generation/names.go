@@ -0,0 +1,165 @@
+package generation
+
+import (
+	"fmt"
+	"go/types"
+
+	gentypes "github.com/efritz/go-genlib/types"
+)
+
+// MethodNames holds the identifiers bound to the parameters and results of a
+// method in generated code.
+type MethodNames struct {
+	Params  []string
+	Results []string
+}
+
+// PositionalNames names parameters v0, v1, ... and results r0, r1, ....
+func PositionalNames(method *gentypes.Method) *MethodNames {
+	params := []string{}
+	for i := range method.Params {
+		params = append(params, fmt.Sprintf("v%d", i))
+	}
+
+	results := []string{}
+	for i := range method.Results {
+		results = append(results, fmt.Sprintf("r%d", i))
+	}
+
+	return &MethodNames{
+		Params:  params,
+		Results: results,
+	}
+}
+
+// SourceNames reuses the parameter and result names of the source declaration,
+// falling back to positional names for names that are blank or would shadow.
+func SourceNames(importNames ImportNames, iface *gentypes.Interface, method *gentypes.Method, outputImportPath string, reserved ...string) *MethodNames {
+	taken := map[string]struct{}{}
+	for _, name := range reserved {
+		taken[name] = struct{}{}
+	}
+
+	for _, typeParam := range iface.TypeParams {
+		taken[typeParam.Name] = struct{}{}
+	}
+
+	for _, name := range types.Universe.Names() {
+		taken[name] = struct{}{}
+	}
+
+	for _, typ := range append(append([]types.Type{}, method.Params...), method.Results...) {
+		collectPackageNames(typ, iface.ImportPath, outputImportPath, importNames, taken)
+	}
+
+	return &MethodNames{
+		Params:  chooseNames(method.ParamNames, len(method.Params), "v", taken),
+		Results: chooseNames(method.ResultNames, len(method.Results), "r", taken),
+	}
+}
+
+func chooseNames(sourceNames []string, n int, prefix string, taken map[string]struct{}) []string {
+	names := make([]string, n)
+	for i := 0; i < n && i < len(sourceNames); i++ {
+		if name := sourceNames[i]; name != "" && name != "_" {
+			if _, ok := taken[name]; !ok {
+				names[i] = name
+				taken[name] = struct{}{}
+			}
+		}
+	}
+
+	for i, name := range names {
+		if name != "" {
+			continue
+		}
+
+		name = fmt.Sprintf("%s%d", prefix, i)
+		for {
+			if _, ok := taken[name]; !ok {
+				break
+			}
+
+			name += "_"
+		}
+
+		names[i] = name
+		taken[name] = struct{}{}
+	}
+
+	return names
+}
+
+func collectPackageNames(
+	typ types.Type,
+	importPath string,
+	outputImportPath string,
+	importNames ImportNames,
+	names map[string]struct{},
+) {
+	recur := func(typ types.Type) {
+		collectPackageNames(typ, importPath, outputImportPath, importNames, names)
+	}
+
+	switch t := typ.(type) {
+	case *types.Array:
+		recur(t.Elem())
+
+	case *types.Basic:
+		if t.Kind() == types.UnsafePointer {
+			names["unsafe"] = struct{}{}
+		}
+
+	case *types.Chan:
+		recur(t.Elem())
+
+	case *types.Interface:
+		for i := 0; i < t.NumEmbeddeds(); i++ {
+			recur(t.EmbeddedType(i))
+		}
+
+		for i := 0; i < t.NumMethods(); i++ {
+			recur(t.Method(i).Type())
+		}
+
+	case *types.Map:
+		recur(t.Key())
+		recur(t.Elem())
+
+	case *types.Named:
+		if pkg := t.Obj().Pkg(); pkg != nil {
+			path := pkg.Path()
+			if path == "" {
+				path = importPath
+			}
+
+			if path = SanitizeImportPath(path, outputImportPath); path != "" {
+				if name, ok := importNames[path]; ok {
+					names[name] = struct{}{}
+				} else {
+					names[pkg.Name()] = struct{}{}
+				}
+			}
+		}
+
+	case *types.Pointer:
+		recur(t.Elem())
+
+	case *types.Signature:
+		for i := 0; i < t.Params().Len(); i++ {
+			recur(t.Params().At(i).Type())
+		}
+
+		for i := 0; i < t.Results().Len(); i++ {
+			recur(t.Results().At(i).Type())
+		}
+
+	case *types.Slice:
+		recur(t.Elem())
+
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			recur(t.Field(i).Type())
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package generation
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/efritz/go-genlib/paths"
+)
+
+const generatedHeaderFormat = "Code generated by %s %s; DO NOT EDIT."
+
+func findPrunableFiles(appName, dirname string, outputs []*output) ([]string, error) {
+	pattern := regexp.MustCompile(fmt.Sprintf(
+		"^// %s$",
+		fmt.Sprintf(regexp.QuoteMeta(generatedHeaderFormat), regexp.QuoteMeta(appName), `\S+`),
+	))
+
+	generated := map[string]struct{}{}
+	for _, output := range outputs {
+		generated[filepath.Clean(output.filename)] = struct{}{}
+	}
+
+	entries, err := ioutil.ReadDir(dirname)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	prunable := []string{}
+	for _, entry := range entries {
+		filename := filepath.Join(dirname, entry.Name())
+
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") {
+			continue
+		}
+
+		if _, ok := generated[filename]; ok {
+			continue
+		}
+
+		owned, err := hasHeader(filename, pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		if owned {
+			prunable = append(prunable, filename)
+		}
+	}
+
+	return prunable, nil
+}
+
+func hasHeader(filename string, pattern *regexp.Regexp) (bool, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	if !scanner.Scan() {
+		return false, scanner.Err()
+	}
+
+	return pattern.MatchString(scanner.Text()), nil
+}
+
+func pruneFiles(filenames []string) error {
+	for _, filename := range filenames {
+		log.Printf(
+			"removing '%s'\n",
+			paths.GetRelativePath(filename),
+		)
+
+		if err := os.Remove(filename); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
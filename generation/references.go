@@ -0,0 +1,202 @@
+package generation
+
+import (
+	"fmt"
+	"go/types"
+	"sort"
+	"strings"
+
+	gentypes "github.com/efritz/go-genlib/types"
+)
+
+// checkReferences returns an error describing every type used by the given
+// interfaces that cannot be referenced from the output package.
+func checkReferences(ifaces []*gentypes.Interface, outputImportPath string) error {
+	if outputImportPath == "" {
+		return nil
+	}
+
+	problems := map[string]struct{}{}
+	for _, iface := range ifaces {
+		check := func(context string, typ types.Type) {
+			walkObjects(typ, func(obj types.Object) {
+				if problem := checkReference(obj, iface.ImportPath, outputImportPath); problem != "" {
+					problems[fmt.Sprintf("%s: %s", context, problem)] = struct{}{}
+				}
+			})
+		}
+
+		for _, typeParam := range iface.TypeParams {
+			check(iface.Name, typeParam.Constraint)
+		}
+
+		for _, method := range iface.Methods {
+			for _, typ := range append(append([]types.Type{}, method.Params...), method.Results...) {
+				check(fmt.Sprintf("%s.%s", iface.Name, method.Name), typ)
+			}
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	messages := []string{}
+	for problem := range problems {
+		messages = append(messages, problem)
+	}
+	sort.Strings(messages)
+
+	return fmt.Errorf(
+		"types cannot be referenced from package %s:\n  %s",
+		outputImportPath,
+		strings.Join(messages, "\n  "),
+	)
+}
+
+func checkReference(obj types.Object, importPath, outputImportPath string) string {
+	if obj.Pkg() == nil {
+		return ""
+	}
+
+	path := obj.Pkg().Path()
+	if path == "" {
+		path = importPath
+	}
+
+	if SanitizeImportPath(path, outputImportPath) == "" {
+		return ""
+	}
+
+	name := fmt.Sprintf("%s.%s", stripVendor(path), obj.Name())
+
+	switch obj.(type) {
+	case *types.Var:
+		if !obj.Exported() {
+			return fmt.Sprintf("anonymous struct has unexported field %s", name)
+		}
+
+		return ""
+
+	case *types.Func:
+		if !obj.Exported() {
+			return fmt.Sprintf("anonymous interface has unexported method %s", name)
+		}
+
+		return ""
+	}
+
+	switch {
+	case !obj.Exported():
+		return fmt.Sprintf("%s is not exported", name)
+
+	case obj.Pkg().Name() == "main":
+		return fmt.Sprintf("%s is declared in a main package", name)
+
+	case strings.HasSuffix(path, "_test"):
+		return fmt.Sprintf("%s is declared in a test package", name)
+
+	case !canImportVendored(outputImportPath, path):
+		return fmt.Sprintf("%s is vendored outside of the output package tree", name)
+
+	case !canImportInternal(stripVendor(outputImportPath), stripVendor(path)):
+		return fmt.Sprintf("%s is declared in an internal package outside of the output package tree", name)
+	}
+
+	return ""
+}
+
+func canImportVendored(importer, path string) bool {
+	index := strings.LastIndex(path, "/vendor/")
+	if index < 0 {
+		return true
+	}
+
+	return isWithin(importer, path[:index])
+}
+
+func canImportInternal(importer, path string) bool {
+	parts := strings.Split(path, "/")
+	for i := len(parts) - 1; i >= 0; i-- {
+		if parts[i] == "internal" {
+			return i > 0 && isWithin(importer, strings.Join(parts[:i], "/"))
+		}
+	}
+
+	return true
+}
+
+func isWithin(path, root string) bool {
+	return path == root || strings.HasPrefix(path, root+"/")
+}
+
+func walkObjects(typ types.Type, f func(obj types.Object)) {
+	recur := func(typ types.Type) {
+		walkObjects(typ, f)
+	}
+
+	recurList := func(typeArgs *types.TypeList) {
+		for i := 0; i < typeArgs.Len(); i++ {
+			recur(typeArgs.At(i))
+		}
+	}
+
+	switch t := typ.(type) {
+	case *types.Alias:
+		f(t.Obj())
+		recurList(t.TypeArgs())
+
+	case *types.Array:
+		recur(t.Elem())
+
+	case *types.Chan:
+		recur(t.Elem())
+
+	case *types.Interface:
+		for i := 0; i < t.NumEmbeddeds(); i++ {
+			recur(t.EmbeddedType(i))
+		}
+
+		for i := 0; i < t.NumExplicitMethods(); i++ {
+			f(t.ExplicitMethod(i))
+			recur(t.ExplicitMethod(i).Type())
+		}
+
+	case *types.Map:
+		recur(t.Key())
+		recur(t.Elem())
+
+	case *types.Named:
+		f(t.Obj())
+		recurList(t.TypeArgs())
+
+	case *types.Pointer:
+		recur(t.Elem())
+
+	case *types.Signature:
+		for i := 0; i < t.Params().Len(); i++ {
+			recur(t.Params().At(i).Type())
+		}
+
+		for i := 0; i < t.Results().Len(); i++ {
+			recur(t.Results().At(i).Type())
+		}
+
+	case *types.Slice:
+		recur(t.Elem())
+
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			if !t.Field(i).Embedded() {
+				f(t.Field(i))
+			}
+
+			recur(t.Field(i).Type())
+		}
+
+	case *types.Union:
+		for i := 0; i < t.Len(); i++ {
+			recur(t.Term(i).Type())
+		}
+	}
+}
@@ -1,8 +1,6 @@
 package generation
 
 import (
-	"fmt"
-
 	"github.com/dave/jennifer/jen"
 	"github.com/efritz/go-genlib/types"
 )
@@ -25,9 +23,20 @@ func GenerateMethod(receiver jen.Code, methodName string, params, results []jen.
 }
 
 func GenerateOverride(receiver jen.Code, importPath, outputImportPath string, method *types.Method, body ...jen.Code) jen.Code {
+	return GenerateOverrideWithNames(receiver, importPath, outputImportPath, method, PositionalNames(method), body...)
+}
+
+func GenerateOverrideWithNames(
+	receiver jen.Code,
+	importPath string,
+	outputImportPath string,
+	method *types.Method,
+	names *MethodNames,
+	body ...jen.Code,
+) jen.Code {
 	params := GenerateParamTypes(method, importPath, outputImportPath, false)
 	for i, param := range params {
-		params[i] = Compose(jen.Id(fmt.Sprintf("v%d", i)), param)
+		params[i] = Compose(jen.Id(names.Params[i]), param)
 	}
 
 	return GenerateMethod(
@@ -68,9 +77,13 @@ func GenerateResultTypes(method *types.Method, importPath, outputImportPath stri
 }
 
 func GenerateDecoratedCall(method *types.Method, target *jen.Statement) jen.Code {
+	return GenerateDecoratedCallWithNames(method, PositionalNames(method), target)
+}
+
+func GenerateDecoratedCallWithNames(method *types.Method, methodNames *MethodNames, target *jen.Statement) jen.Code {
 	names := []jen.Code{}
 	for i := range method.Params {
-		name := jen.Id(fmt.Sprintf("v%d", i))
+		name := jen.Id(methodNames.Params[i])
 		if method.Variadic && i == len(method.Params)-1 {
 			name = Compose(name, jen.Op("..."))
 		}
@@ -83,22 +96,26 @@ func GenerateDecoratedCall(method *types.Method, target *jen.Statement) jen.Code
 		return dispatch
 	}
 
-	assign := jen.Id("r0")
+	assign := jen.Id(methodNames.Results[0])
 	for i := 1; i < len(method.Results); i++ {
-		assign = assign.Op(",").Id(fmt.Sprintf("r%d", i))
+		assign = assign.Op(",").Id(methodNames.Results[i])
 	}
 
 	return Compose(assign.Op(":="), dispatch)
 }
 
 func GenerateDecoratedReturn(method *types.Method) jen.Code {
+	return GenerateDecoratedReturnWithNames(method, PositionalNames(method))
+}
+
+func GenerateDecoratedReturnWithNames(method *types.Method, methodNames *MethodNames) jen.Code {
 	ret := jen.Return()
 
 	if len(method.Results) > 0 {
-		ret = ret.Id("r0")
+		ret = ret.Id(methodNames.Results[0])
 
 		for i := 1; i < len(method.Results); i++ {
-			ret = ret.Op(",").Id(fmt.Sprintf("r%d", i))
+			ret = ret.Op(",").Id(methodNames.Results[i])
 		}
 	}
 
@@ -10,7 +10,7 @@ import (
 
 func SanitizeImportPath(path, outputImportPath string) string {
 	path = stripVendor(path)
-	if path == outputImportPath {
+	if path == stripVendor(outputImportPath) {
 		return ""
 	}
 
@@ -23,8 +23,18 @@ func GenerateType(typ types.Type, importPath, outputImportPath string, variadic
 	}
 
 	switch t := typ.(type) {
+	case *types.Alias:
+		return generateQualifiedName(t.Obj(), t.TypeArgs(), importPath, outputImportPath)
+
+	case *types.Array:
+		return Compose(jen.Index(jen.Lit(int(t.Len()))), recur(t.Elem()))
+
 	case *types.Basic:
-		return jen.Id(typ.String())
+		if t.Kind() == types.UnsafePointer {
+			return jen.Qual("unsafe", "Pointer")
+		}
+
+		return jen.Id(t.Name())
 
 	case *types.Chan:
 		if t.Dir() == types.RecvOnly {
@@ -38,33 +48,34 @@ func GenerateType(typ types.Type, importPath, outputImportPath string, variadic
 		return Compose(jen.Chan(), recur(t.Elem()))
 
 	case *types.Interface:
-		methods := []jen.Code{}
-		for i := 0; i < t.NumMethods(); i++ {
-			methods = append(methods, Compose(jen.Id(t.Method(i).Name()), recur(t.Method(i).Type())))
+		if t.IsImplicit() && t.NumEmbeddeds() == 1 {
+			return recur(t.EmbeddedType(0))
+		}
+
+		elements := []jen.Code{}
+		for i := 0; i < t.NumEmbeddeds(); i++ {
+			elements = append(elements, recur(t.EmbeddedType(i)))
+		}
+
+		for i := 0; i < t.NumExplicitMethods(); i++ {
+			method := t.ExplicitMethod(i)
+			params, results := generateSignature(method.Type().(*types.Signature), importPath, outputImportPath)
+			elements = append(elements, jen.Id(method.Name()).Params(params...).Params(results...))
 		}
 
-		return jen.Interface(methods...)
+		return jen.Interface(elements...)
 
 	case *types.Map:
 		return Compose(jen.Map(recur(t.Key())), recur(t.Elem()))
 
 	case *types.Named:
-		return generateQualifiedName(t, importPath, outputImportPath)
+		return generateQualifiedName(t.Obj(), t.TypeArgs(), importPath, outputImportPath)
 
 	case *types.Pointer:
 		return Compose(jen.Op("*"), recur(t.Elem()))
 
 	case *types.Signature:
-		params := []jen.Code{}
-		for i := 0; i < t.Params().Len(); i++ {
-			params = append(params, Compose(jen.Id(t.Params().At(i).Name()), recur(t.Params().At(i).Type())))
-		}
-
-		results := []jen.Code{}
-		for i := 0; i < t.Results().Len(); i++ {
-			results = append(results, recur(t.Results().At(i).Type()))
-		}
-
+		params, results := generateSignature(t, importPath, outputImportPath)
 		return jen.Func().Params(params...).Params(results...)
 
 	case *types.Slice:
@@ -73,35 +84,100 @@ func GenerateType(typ types.Type, importPath, outputImportPath string, variadic
 	case *types.Struct:
 		fields := []jen.Code{}
 		for i := 0; i < t.NumFields(); i++ {
-			fields = append(fields, Compose(jen.Id(t.Field(i).Name()), recur(t.Field(i).Type())))
+			field := recur(t.Field(i).Type())
+			if !t.Field(i).Embedded() {
+				field = Compose(jen.Id(t.Field(i).Name()), field)
+			}
+
+			// Tags are part of the identity of a struct type.
+			if tag := t.Tag(i); tag != "" {
+				field = Compose(field, generateTag(tag))
+			}
+
+			fields = append(fields, field)
 		}
 
 		return jen.Struct(fields...)
 
+	case *types.TypeParam:
+		return jen.Id(t.Obj().Name())
+
+	case *types.Union:
+		terms := []jen.Code{}
+		for i := 0; i < t.Len(); i++ {
+			term := recur(t.Term(i).Type())
+			if t.Term(i).Tilde() {
+				term = Compose(jen.Op("~"), term)
+			}
+
+			terms = append(terms, term)
+		}
+
+		return jen.Union(terms...)
+
 	default:
 		panic(fmt.Sprintf("unsupported case: %#v\n", typ))
 	}
 }
 
+func generateSignature(signature *types.Signature, importPath, outputImportPath string) (params, results []jen.Code) {
+	for i := 0; i < signature.Params().Len(); i++ {
+		param := signature.Params().At(i)
+		variadic := signature.Variadic() && i == signature.Params().Len()-1
+		params = append(params, Compose(jen.Id(param.Name()), GenerateType(param.Type(), importPath, outputImportPath, variadic)))
+	}
+
+	for i := 0; i < signature.Results().Len(); i++ {
+		results = append(results, GenerateType(signature.Results().At(i).Type(), importPath, outputImportPath, false))
+	}
+
+	return params, results
+}
+
+func generateTag(tag string) jen.Code {
+	if strings.Contains(tag, "`") {
+		return jen.Lit(tag)
+	}
+
+	return jen.Op("`" + tag + "`")
+}
+
 func stripVendor(path string) string {
 	parts := strings.Split(path, "/vendor/")
 	return parts[len(parts)-1]
 }
 
-func generateQualifiedName(t *types.Named, importPath, outputImportPath string) *jen.Statement {
-	name := t.Obj().Name()
+func generateQualifiedName(obj *types.TypeName, typeArgs *types.TypeList, importPath, outputImportPath string) *jen.Statement {
+	return withTypeArgs(generateUnqualifiedName(obj, importPath, outputImportPath), typeArgs, importPath, outputImportPath)
+}
+
+func generateUnqualifiedName(obj *types.TypeName, importPath, outputImportPath string) *jen.Statement {
+	name := obj.Name()
 
-	if t.Obj().Pkg() == nil {
+	if obj.Pkg() == nil {
 		return jen.Id(name)
 	}
 
-	if path := t.Obj().Pkg().Path(); path != "" {
+	if path := obj.Pkg().Path(); path != "" {
 		return jen.Qual(SanitizeImportPath(path, outputImportPath), name)
 	}
 
 	return jen.Qual(SanitizeImportPath(importPath, outputImportPath), name)
 }
 
+func withTypeArgs(name *jen.Statement, typeArgs *types.TypeList, importPath, outputImportPath string) *jen.Statement {
+	if typeArgs.Len() == 0 {
+		return name
+	}
+
+	args := []jen.Code{}
+	for i := 0; i < typeArgs.Len(); i++ {
+		args = append(args, GenerateType(typeArgs.At(i), importPath, outputImportPath, false))
+	}
+
+	return name.Types(args...)
+}
+
 func getSliceTypePrefix(variadic bool) *jen.Statement {
 	if variadic {
 		return jen.Op("...")
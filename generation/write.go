@@ -0,0 +1,117 @@
+package generation
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/efritz/go-genlib/paths"
+)
+
+func writeFile(filename, content string) error {
+	log.Printf(
+		"writing to '%s'\n",
+		paths.GetRelativePath(filename),
+	)
+
+	tempName, err := stageFile(filename, content)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Rename(tempName, filename); err != nil {
+		_ = os.Remove(tempName)
+		return err
+	}
+
+	return nil
+}
+
+func writeFilesAllOrNothing(outputs []*output) error {
+	tempNames := []string{}
+	cleanup := func() {
+		for _, tempName := range tempNames {
+			_ = os.Remove(tempName)
+		}
+	}
+
+	for _, output := range outputs {
+		tempName, err := stageFile(output.filename, output.content)
+		if err != nil {
+			cleanup()
+			return err
+		}
+
+		tempNames = append(tempNames, tempName)
+	}
+
+	for i, output := range outputs {
+		log.Printf(
+			"writing to '%s'\n",
+			paths.GetRelativePath(output.filename),
+		)
+
+		if err := os.Rename(tempNames[i], output.filename); err != nil {
+			tempNames = tempNames[i:]
+			cleanup()
+
+			if rollbackErr := rollback(outputs[:i]); rollbackErr != nil {
+				return fmt.Errorf("%s (rollback failed: %s)", err.Error(), rollbackErr.Error())
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+func rollback(outputs []*output) error {
+	for _, output := range outputs {
+		if output.status == statusCreate {
+			if err := os.Remove(output.filename); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err := writeFile(output.filename, output.previous); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func stageFile(filename, content string) (string, error) {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(filename); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	file, err := ioutil.TempFile(filepath.Dir(filename), fmt.Sprintf(".%s.*.tmp", filepath.Base(filename)))
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := file.WriteString(content); err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return "", err
+	}
+
+	if err := file.Close(); err != nil {
+		_ = os.Remove(file.Name())
+		return "", err
+	}
+
+	if err := os.Chmod(file.Name(), mode); err != nil {
+		_ = os.Remove(file.Name())
+		return "", err
+	}
+
+	return file.Name(), nil
+}
@@ -6,71 +6,70 @@ import (
 	"github.com/dave/jennifer/jen"
 )
 
+// GenerateZeroValue generates an expression that evaluates to the zero value of
+// the given type. The expression has the given type even when it is not assigned
+// to a typed location, except for pointers, slices, maps, channels, functions, and
+// interfaces, whose zero value is nil.
 func GenerateZeroValue(typ types.Type, importPath, outputImportPath string) *jen.Statement {
 	switch t := typ.(type) {
+	case *types.Alias:
+		return GenerateZeroValue(types.Unalias(t), importPath, outputImportPath)
+
+	case *types.Array:
+		return GenerateType(typ, importPath, outputImportPath, false).Values()
+
 	case *types.Basic:
-		kind := t.Kind()
-
-		if kind == types.Bool {
-			return jen.False()
-		} else if kind == types.String {
-			return jen.Lit("")
-		} else if isIntegerType(kind) {
-			return jen.Lit(0)
-		}
+		switch t.Kind() {
+		case types.Int8, types.Int16, types.Int32, types.Int64,
+			types.Uint, types.Uint8, types.Uint16, types.Uint32, types.Uint64, types.Uintptr,
+			types.Float32, types.Complex64:
+			// An untyped constant would default to int, float64, or complex128.
+			return jen.Id(t.Name()).Call(generateBasicZeroValue(t))
 
-	case *types.Named:
-		if shouldEmitNamedType(t) {
-			return Compose(generateQualifiedName(t, importPath, outputImportPath), jen.Block())
+		case types.UnsafePointer:
+			return jen.Qual("unsafe", "Pointer").Call(jen.Nil())
 		}
 
-		return GenerateZeroValue(t.Underlying(), importPath, outputImportPath)
+		return generateBasicZeroValue(t)
 
-	case *types.Struct:
-		return GenerateType(typ, importPath, outputImportPath, false).Block()
-	}
-
-	return jen.Nil()
-}
+	case *types.Named:
+		name := generateQualifiedName(t.Obj(), t.TypeArgs(), importPath, outputImportPath)
 
-func isIntegerType(kind types.BasicKind) bool {
-	kinds := []types.BasicKind{
-		types.Int,
-		types.Int8,
-		types.Int16,
-		types.Int32,
-		types.Int64,
-		types.Uint,
-		types.Uint8,
-		types.Uint16,
-		types.Uint32,
-		types.Uint64,
-		types.Uintptr,
-		types.Float32,
-		types.Float64,
-		types.Byte,
-		types.Rune,
-		types.Complex64,
-		types.Complex128,
-	}
+		switch underlying := t.Underlying().(type) {
+		case *types.Array, *types.Struct:
+			return name.Values()
 
-	for _, k := range kinds {
-		if k == kind {
-			return true
+		case *types.Basic:
+			return name.Call(generateBasicZeroValue(underlying))
 		}
+
+	case *types.Struct:
+		return GenerateType(typ, importPath, outputImportPath, false).Values()
+
+	case *types.TypeParam:
+		return jen.Op("*").New(jen.Id(t.Obj().Name()))
 	}
 
-	return false
+	return jen.Nil()
 }
 
-func shouldEmitNamedType(t *types.Named) bool {
-	if _, ok := t.Underlying().(*types.Struct); ok {
-		return true
-	}
+func generateBasicZeroValue(t *types.Basic) *jen.Statement {
+	info := t.Info()
 
-	if _, ok := t.Underlying().(*types.Array); ok {
-		return true
+	switch {
+	case info&types.IsBoolean != 0:
+		return jen.False()
+	case info&types.IsString != 0:
+		return jen.Lit("")
+	case info&types.IsInteger != 0:
+		return jen.Lit(0)
+	case info&types.IsFloat != 0:
+		return jen.Lit(0.0)
+	case info&types.IsComplex != 0:
+		// jen.Lit renders complex constants as `(0 + 0i)`
+		return jen.Op("0i")
 	}
 
-	return false
+	// The remaining kinds are unsafe.Pointer, untyped nil, and invalid types.
+	return jen.Nil()
 }
@@ -0,0 +1,142 @@
+package generation
+
+import (
+	"bytes"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+const (
+	zeroValueFixturePath = "example.com/fixture"
+	zeroValueOutputPath  = "example.com/output"
+)
+
+const zeroValueFixture = `
+package fixture
+
+import (
+	"time"
+	"unsafe"
+)
+
+type (
+	Duration int64
+	Point    struct{ X, Y int }
+	Grid     [2]Point
+	Pointer  unsafe.Pointer
+)
+
+var (
+	Durations [3]Duration
+	Times     [2]time.Duration
+	Points    [2]Point
+	Struct    struct{ D time.Duration; P Point; G *Grid }
+	Nested    struct{ Inner struct{ D Duration } }
+)
+
+func Generic[T any]() (t T) { return }
+`
+
+func TestGenerateZeroValue(t *testing.T) {
+	// A single importer is shared so that both packages see the same time package.
+	std := importer.Default()
+	pkg, _ := checkZeroValueSource(t, zeroValueFixturePath, zeroValueFixture, std)
+	lookup := func(name string) types.Type { return pkg.Scope().Lookup(name).Type() }
+
+	timePkg, err := std.Import("time")
+	if err != nil {
+		t.Fatalf("failed to import time: %s", err)
+	}
+
+	testCases := map[string]types.Type{
+		"named basic":              lookup("Duration"),
+		"qualified named basic":    timePkg.Scope().Lookup("Duration").Type(),
+		"named struct":             lookup("Point"),
+		"named array":              lookup("Grid"),
+		"named unsafe pointer":     lookup("Pointer"),
+		"array of named basic":     lookup("Durations"),
+		"array of qualified basic": lookup("Times"),
+		"array of named struct":    lookup("Points"),
+		"struct":                   lookup("Struct"),
+		"nested struct":            lookup("Nested"),
+		"type parameter":           lookup("Generic").(*types.Signature).Results().At(0).Type(),
+	}
+
+	for kind := types.Bool; kind <= types.UnsafePointer; kind++ {
+		testCases[types.Typ[kind].String()] = types.Typ[kind]
+	}
+
+	imports := importerFunc(func(path string) (*types.Package, error) {
+		if path == zeroValueFixturePath {
+			return pkg, nil
+		}
+
+		return std.Import(path)
+	})
+
+	for name, typ := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// The declarations are wrapped in a generic function that binds the
+			// name of the fixture's type parameter. The type of got is inferred
+			// from the zero value alone.
+			file := jen.NewFilePath(zeroValueOutputPath)
+			file.Func().Id("_").Types(jen.Id("T").Any()).Params().Block(
+				jen.Var().Id("got").Op("=").Add(
+					GenerateZeroValue(typ, zeroValueFixturePath, zeroValueOutputPath),
+				),
+				jen.Var().Id("want").Add(
+					GenerateType(typ, zeroValueFixturePath, zeroValueOutputPath, false),
+				),
+				jen.List(jen.Id("_"), jen.Id("_")).Op("=").List(jen.Id("got"), jen.Id("want")),
+			)
+
+			buffer := &bytes.Buffer{}
+			if err := file.Render(buffer); err != nil {
+				t.Fatalf("failed to render zero value of %s: %s", typ, err)
+			}
+
+			_, info := checkZeroValueSource(t, zeroValueOutputPath, buffer.String(), imports)
+
+			declared := map[string]types.Type{}
+			for ident, obj := range info.Defs {
+				if obj != nil {
+					declared[ident.Name] = obj.Type()
+				}
+			}
+
+			if !types.Identical(declared["got"], declared["want"]) {
+				t.Errorf("unexpected type of zero value. want=%s have=%s\n%s", declared["want"], declared["got"], buffer.String())
+			}
+		})
+	}
+}
+
+func checkZeroValueSource(t *testing.T, path, source string, imports types.Importer) (*types.Package, *types.Info) {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "source.go", source, 0)
+	if err != nil {
+		t.Fatalf("failed to parse source (%s):\n%s", err, source)
+	}
+
+	info := &types.Info{Defs: map[*ast.Ident]types.Object{}}
+	pkg, err := (&types.Config{Importer: imports}).Check(path, fset, []*ast.File{file}, info)
+	if err != nil {
+		t.Fatalf("failed to type-check source (%s):\n%s", err, source)
+	}
+
+	return pkg, info
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) {
+	return f(path)
+}
@@ -1,13 +1,19 @@
 module github.com/efritz/go-genlib
 
-go 1.13
+go 1.25.0
 
 require (
 	github.com/alecthomas/kingpin v2.2.6+incompatible
+	github.com/dave/jennifer v1.7.1
+	github.com/mitchellh/go-wordwrap v1.0.0
+	golang.org/x/tools v0.44.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
 	github.com/alecthomas/template v0.0.0-20190718012654-fb15b899a751 // indirect
 	github.com/alecthomas/units v0.0.0-20190924025748-f65c72e2690d // indirect
-	github.com/dave/jennifer v1.4.0
 	github.com/davecgh/go-spew v1.1.1 // indirect
-	github.com/mitchellh/go-wordwrap v1.0.0
-	golang.org/x/tools v0.0.0-20191224055732-dd894d0a8a40
+	golang.org/x/mod v0.35.0 // indirect
+	golang.org/x/sync v0.20.0 // indirect
 )
@@ -13,7 +13,13 @@ func GetRelativePath(path string) string {
 		return path
 	}
 
-	wd, err = filepath.EvalSymlinks(wd)
+	return GetRelativePathFrom(wd, path)
+}
+
+// GetRelativePathFrom is like GetRelativePath but is relative to the given
+// directory instead of the current working directory.
+func GetRelativePathFrom(wd, path string) string {
+	wd, err := filepath.EvalSymlinks(wd)
 	if err != nil {
 		return path
 	}
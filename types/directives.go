@@ -0,0 +1,20 @@
+package types
+
+const (
+	DirectivePrefix   = "genlib:"
+	GenerateDirective = "generate"
+)
+
+// Directives maps the name of each `//genlib:<name> key=value ...` comment
+// attached to a declaration to its arguments.
+type Directives map[string]map[string]string
+
+func (d Directives) Has(name string) bool {
+	_, ok := d[name]
+	return ok
+}
+
+func (d Directives) Get(name, key string) (string, bool) {
+	value, ok := d[name][key]
+	return value, ok
+}
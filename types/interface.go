@@ -10,7 +10,12 @@ type (
 		Name       string
 		ImportPath string
 		Type       InterfaceType
+		TypeParams []*TypeParam
 		Methods    []*Method
+		Doc        string
+		Directives Directives
+		Filename   string
+		Line       int
 	}
 
 	InterfaceType int
@@ -19,6 +24,8 @@ type (
 const (
 	InterfaceTypeStruct InterfaceType = iota
 	InterfaceTypeInterface
+	InterfaceTypeMethodSet
+	InterfaceTypePointerMethodSet
 )
 
 func (i *Interface) MethodNames() []string {
@@ -96,3 +103,41 @@ func DeconstructInterface(name, importPath string, typeSpec *types.Interface) *I
 		Methods:    methods,
 	}
 }
+
+// DeconstructMethodSet creates an interface from the method set of T or *T.
+// Unexported methods are skipped as they cannot be implemented elsewhere.
+func DeconstructMethodSet(name, importPath string, typ types.Type) *Interface {
+	methodSet := types.NewMethodSet(typ)
+	methodMap := map[string]*Method{}
+	methodNames := []string{}
+
+	for i := 0; i < methodSet.Len(); i++ {
+		method := methodSet.At(i).Obj()
+		name := method.Name()
+		if !method.Exported() {
+			continue
+		}
+
+		methodMap[name] = DeconstructMethod(name, method.Type().(*types.Signature))
+		methodNames = append(methodNames, name)
+	}
+
+	sort.Strings(methodNames)
+
+	methods := []*Method{}
+	for _, name := range methodNames {
+		methods = append(methods, methodMap[name])
+	}
+
+	ifaceType := InterfaceTypeMethodSet
+	if _, ok := typ.(*types.Pointer); ok {
+		ifaceType = InterfaceTypePointerMethodSet
+	}
+
+	return &Interface{
+		Name:       name,
+		ImportPath: importPath,
+		Type:       ifaceType,
+		Methods:    methods,
+	}
+}
@@ -3,32 +3,44 @@ package types
 import "go/types"
 
 type Method struct {
-	Name     string
-	Params   []types.Type
-	Results  []types.Type
-	Variadic bool
+	Name        string
+	Params      []types.Type
+	ParamNames  []string
+	Results     []types.Type
+	ResultNames []string
+	Variadic    bool
+	Doc         string
+	Directives  Directives
+	Filename    string
+	Line        int
 }
 
 func DeconstructMethod(name string, signature *types.Signature) *Method {
 	var (
-		ps      = signature.Params()
-		rs      = signature.Results()
-		params  = []types.Type{}
-		results = []types.Type{}
+		ps          = signature.Params()
+		rs          = signature.Results()
+		params      = []types.Type{}
+		paramNames  = []string{}
+		results     = []types.Type{}
+		resultNames = []string{}
 	)
 
 	for i := 0; i < ps.Len(); i++ {
 		params = append(params, ps.At(i).Type())
+		paramNames = append(paramNames, ps.At(i).Name())
 	}
 
 	for i := 0; i < rs.Len(); i++ {
 		results = append(results, rs.At(i).Type())
+		resultNames = append(resultNames, rs.At(i).Name())
 	}
 
 	return &Method{
-		Name:     name,
-		Params:   params,
-		Results:  results,
-		Variadic: signature.Variadic(),
+		Name:        name,
+		Params:      params,
+		ParamNames:  paramNames,
+		Results:     results,
+		ResultNames: resultNames,
+		Variadic:    signature.Variadic(),
 	}
 }
@@ -1,13 +1,19 @@
 package types
 
 type Package struct {
-	Name  string
-	Types map[string]*Interface
+	ID                string
+	Name              string
+	Types             map[string]*Interface
+	MethodSets        map[string]*Interface
+	PointerMethodSets map[string]*Interface
 }
 
-func NewPackage(name string, types map[string]*Interface) *Package {
+func NewPackage(id, name string, types, methodSets, pointerMethodSets map[string]*Interface) *Package {
 	return &Package{
-		Name:  name,
-		Types: types,
+		ID:                id,
+		Name:              name,
+		Types:             types,
+		MethodSets:        methodSets,
+		PointerMethodSets: pointerMethodSets,
 	}
 }
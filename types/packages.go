@@ -3,6 +3,7 @@ package types
 import (
 	"fmt"
 	"sort"
+	"strings"
 )
 
 type (
@@ -11,19 +12,48 @@ type (
 	}
 
 	TypeGetter func(pkgs *Packages, name string) (*Interface, error)
+
+	// AmbiguousTypeError is returned when a type name is declared by more than
+	// one of the supplied packages.
+	AmbiguousTypeError struct {
+		Name       string
+		PackageIDs []string
+	}
 )
 
+func (e *AmbiguousTypeError) Error() string {
+	return fmt.Sprintf(
+		"type '%s' is multiply-defined in supplied import paths (%s)",
+		e.Name,
+		strings.Join(e.PackageIDs, ", "),
+	)
+}
+
 func NewPackages(packages map[string]*Package) *Packages {
 	return &Packages{
 		packages: packages,
 	}
 }
 
+// Subset returns the packages with the given IDs.
+func (p *Packages) Subset(ids ...string) *Packages {
+	packages := map[string]*Package{}
+	for _, id := range ids {
+		if pkg, ok := p.packages[id]; ok {
+			packages[id] = pkg
+		}
+	}
+
+	return NewPackages(packages)
+}
+
 func (p *Packages) GetNames() []string {
 	nameMap := map[string]struct{}{}
 	for _, pkg := range p.packages {
-		for name, _ := range pkg.Types {
-			nameMap[name] = struct{}{}
+		for _, typeMap := range []map[string]*Interface{pkg.Types, pkg.MethodSets, pkg.PointerMethodSets} {
+			for name := range typeMap {
+				nameMap[name] = struct{}{}
+			}
 		}
 	}
 
@@ -40,18 +70,37 @@ func (p *Packages) GetType(name string) (*Interface, error)      { return p.getT
 func (p *Packages) GetStruct(name string) (*Interface, error)    { return p.getType(name, sType) }
 func (p *Packages) GetInterface(name string) (*Interface, error) { return p.getType(name, iType) }
 
+func (p *Packages) GetMethodSet(name string) (*Interface, error) {
+	return p.lookup(name, mSets, aType)
+}
+
+func (p *Packages) GetPointerMethodSet(name string) (*Interface, error) {
+	return p.lookup(name, pSets, aType)
+}
+
 func (p *Packages) getType(name string, matcher func(InterfaceType) bool) (*Interface, error) {
+	return p.lookup(name, decls, matcher)
+}
+
+func (p *Packages) lookup(
+	name string,
+	selector func(pkg *Package) map[string]*Interface,
+	matcher func(InterfaceType) bool,
+) (*Interface, error) {
 	candidates := []*Interface{}
+	ids := []string{}
 	for _, pkg := range p.packages {
-		if t, ok := pkg.Types[name]; ok {
+		if t, ok := selector(pkg)[name]; ok {
 			if matcher(t.Type) {
 				candidates = append(candidates, t)
+				ids = append(ids, pkg.ID)
 			}
 		}
 	}
 
 	if len(candidates) > 1 {
-		return nil, fmt.Errorf("type '%s' is multiply-defined in supplied import paths", name)
+		sort.Strings(ids)
+		return nil, &AmbiguousTypeError{Name: name, PackageIDs: ids}
 	}
 
 	if len(candidates) == 1 {
@@ -64,9 +113,16 @@ func (p *Packages) getType(name string, matcher func(InterfaceType) bool) (*Inte
 //
 // Helpers
 
+func decls(pkg *Package) map[string]*Interface                     { return pkg.Types }
+func mSets(pkg *Package) map[string]*Interface                     { return pkg.MethodSets }
+func pSets(pkg *Package) map[string]*Interface                     { return pkg.PointerMethodSets }
 func aType(ifaceType InterfaceType) bool                           { return true }
 func sType(ifaceType InterfaceType) bool                           { return ifaceType == InterfaceTypeStruct }
 func iType(ifaceType InterfaceType) bool                           { return ifaceType == InterfaceTypeInterface }
 func GetType(pkgs *Packages, name string) (*Interface, error)      { return pkgs.GetType(name) }
 func GetStruct(pkgs *Packages, name string) (*Interface, error)    { return pkgs.GetStruct(name) }
 func GetInterface(pkgs *Packages, name string) (*Interface, error) { return pkgs.GetInterface(name) }
+func GetMethodSet(pkgs *Packages, name string) (*Interface, error) { return pkgs.GetMethodSet(name) }
+func GetPointerMethodSet(pkgs *Packages, name string) (*Interface, error) {
+	return pkgs.GetPointerMethodSet(name)
+}
@@ -0,0 +1,22 @@
+package types
+
+import "go/types"
+
+type TypeParam struct {
+	Name       string
+	Constraint types.Type
+}
+
+func DeconstructTypeParams(typeParams *types.TypeParamList) []*TypeParam {
+	params := []*TypeParam{}
+	for i := 0; i < typeParams.Len(); i++ {
+		typeParam := typeParams.At(i)
+
+		params = append(params, &TypeParam{
+			Name:       typeParam.Obj().Name(),
+			Constraint: typeParam.Constraint(),
+		})
+	}
+
+	return params
+}